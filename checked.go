@@ -0,0 +1,84 @@
+package iterutil
+
+import (
+	"iter"
+	"sync/atomic"
+)
+
+// Checked returns an iterator that behaves like seq
+// but panics if seq misuses the yield function it receives, i.e. if seq
+//   - calls yield after yield has returned false,
+//   - calls yield after seq itself has returned, or
+//   - calls yield while a previous call to yield has yet to return
+//     (whether concurrently, from another goroutine,
+//     or re-entrantly, from within the loop body).
+//
+// Checked is primarily intended for testing custom iterators.
+func Checked[E any](seq iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		var c checker
+		defer c.exit()
+		seq(func(e E) bool {
+			c.enter()
+			ok := yield(e)
+			c.leave(ok)
+			return ok
+		})
+	}
+}
+
+// Checked2 returns an iterator that behaves like seq
+// but panics if seq misuses the yield function it receives;
+// see [Checked] for more details.
+func Checked2[K, V any](seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var c checker
+		defer c.exit()
+		seq(func(k K, v V) bool {
+			c.enter()
+			ok := yield(k, v)
+			c.leave(ok)
+			return ok
+		})
+	}
+}
+
+// A checker tracks the state of one invocation of an iterator.
+// Because the iterator may (incorrectly) call yield from several goroutines,
+// all state transitions are atomic.
+type checker struct {
+	state atomic.Int32
+}
+
+const (
+	stateReady    int32 = iota // yield can be called
+	stateYielding              // a call to yield is in progress
+	stateDone                  // yield returned false
+	stateExited                // the iterator returned
+)
+
+func (c *checker) enter() {
+	if c.state.CompareAndSwap(stateReady, stateYielding) {
+		return
+	}
+	switch c.state.Load() {
+	case stateYielding:
+		panic("yield called concurrently or re-entrantly")
+	case stateDone:
+		panic("yield called after it returned false")
+	default: // stateExited
+		panic("yield called after the iterator returned")
+	}
+}
+
+func (c *checker) leave(ok bool) {
+	next := stateReady
+	if !ok {
+		next = stateDone
+	}
+	c.state.CompareAndSwap(stateYielding, next)
+}
+
+func (c *checker) exit() {
+	c.state.Store(stateExited)
+}
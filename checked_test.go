@@ -0,0 +1,166 @@
+package iterutil_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jub0bs/iterutil"
+)
+
+func ExampleChecked() {
+	// faulty ignores the result of yield, which is a bug.
+	faulty := func(yield func(int) bool) {
+		yield(1)
+		yield(2)
+	}
+	defer func() {
+		fmt.Println(recover())
+	}()
+	for i := range iterutil.Checked(faulty) {
+		fmt.Println(i)
+		break
+	}
+	// Output:
+	// 1
+	// yield called after it returned false
+}
+
+func TestChecked(t *testing.T) {
+	cases := []struct {
+		desc      string
+		run       func()
+		wantPanic any
+	}{
+		{
+			desc: "well behaved",
+			run: func() {
+				seq := iterutil.Checked(slices.Values([]int{1, 2, 3}))
+				seq(func(i int) bool { return i < 2 })
+			},
+		}, {
+			desc: "yield called after it returned false",
+			run: func() {
+				seq := func(yield func(int) bool) {
+					yield(1)
+					yield(2)
+				}
+				iterutil.Checked(seq)(func(int) bool { return false })
+			},
+			wantPanic: "yield called after it returned false",
+		}, {
+			desc: "yield called after the iterator returned",
+			run: func() {
+				var leaked func(int) bool
+				seq := func(yield func(int) bool) {
+					leaked = yield
+				}
+				iterutil.Checked(seq)(func(int) bool { return true })
+				leaked(1)
+			},
+			wantPanic: "yield called after the iterator returned",
+		}, {
+			desc: "yield called re-entrantly",
+			run: func() {
+				var leaked func(int) bool
+				seq := func(yield func(int) bool) {
+					leaked = yield
+					yield(0)
+				}
+				iterutil.Checked(seq)(func(i int) bool {
+					if i == 0 {
+						leaked(1)
+					}
+					return true
+				})
+			},
+			wantPanic: "yield called concurrently or re-entrantly",
+		}, {
+			desc: "yield called concurrently",
+			run: func() {
+				entered := make(chan struct{})
+				release := make(chan struct{})
+				seq := func(yield func(int) bool) {
+					done := make(chan struct{})
+					go func() {
+						defer close(done)
+						yield(0)
+					}()
+					<-entered
+					defer func() {
+						close(release)
+						<-done
+					}()
+					yield(1)
+				}
+				iterutil.Checked(seq)(func(i int) bool {
+					if i == 0 {
+						close(entered)
+						<-release
+					}
+					return true
+				})
+			},
+			wantPanic: "yield called concurrently or re-entrantly",
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.wantPanic {
+					t.Errorf("got panic %v; want %v", r, tc.wantPanic)
+				}
+			}()
+			tc.run()
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestChecked2(t *testing.T) {
+	cases := []struct {
+		desc      string
+		run       func()
+		wantPanic any
+	}{
+		{
+			desc: "well behaved",
+			run: func() {
+				seq := iterutil.Checked2(slices.All([]int{1, 2, 3}))
+				seq(func(i, _ int) bool { return i < 2 })
+			},
+		}, {
+			desc: "yield called after it returned false",
+			run: func() {
+				seq := func(yield func(int, int) bool) {
+					yield(0, 1)
+					yield(1, 2)
+				}
+				iterutil.Checked2(seq)(func(int, int) bool { return false })
+			},
+			wantPanic: "yield called after it returned false",
+		}, {
+			desc: "yield called after the iterator returned",
+			run: func() {
+				var leaked func(int, int) bool
+				seq := func(yield func(int, int) bool) {
+					leaked = yield
+				}
+				iterutil.Checked2(seq)(func(int, int) bool { return true })
+				leaked(0, 1)
+			},
+			wantPanic: "yield called after the iterator returned",
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tc.wantPanic {
+					t.Errorf("got panic %v; want %v", r, tc.wantPanic)
+				}
+			}()
+			tc.run()
+		}
+		t.Run(tc.desc, f)
+	}
+}
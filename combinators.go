@@ -2,6 +2,7 @@ package iterutil
 
 import (
 	"iter"
+	"slices"
 
 	"golang.org/x/exp/constraints"
 )
@@ -102,12 +103,13 @@ func DropWhile[E any](seq iter.Seq[E], p func(E) bool) iter.Seq[E] {
 // whose length is min(max(count, 0), Len(seq)).
 func Take[I constraints.Integer, E any](seq iter.Seq[E], count I) iter.Seq[E] {
 	return func(yield func(E) bool) {
+		n := count // so that the resulting iterator be re-iterable
 		for e := range seq {
-			if count > 0 {
+			if n > 0 {
 				if !yield(e) {
 					return
 				}
-				count--
+				n--
 				continue
 			}
 			return
@@ -119,9 +121,10 @@ func Take[I constraints.Integer, E any](seq iter.Seq[E], count I) iter.Seq[E] {
 // after the first min(max(count, 0), Len(seq)) elements.
 func Drop[I constraints.Integer, E any](seq iter.Seq[E], count I) iter.Seq[E] {
 	return func(yield func(E) bool) {
+		n := count // so that the resulting iterator be re-iterable
 		for e := range seq {
-			if count > 0 {
-				count--
+			if n > 0 {
+				n--
 				continue
 			}
 			if !yield(e) {
@@ -131,6 +134,103 @@ func Drop[I constraints.Integer, E any](seq iter.Seq[E], count I) iter.Seq[E] {
 	}
 }
 
+// StepBy, if step is positive, returns an iterator over every step-th element
+// of seq, starting with the first one; otherwise, it panics.
+func StepBy[I constraints.Integer, E any](seq iter.Seq[E], step I) iter.Seq[E] {
+	return Stride(seq, 0, step)
+}
+
+// Stride, if offset is non-negative and step is positive,
+// returns an iterator over every step-th element of seq,
+// starting with the element at index offset; otherwise, it panics.
+func Stride[I constraints.Integer, E any](seq iter.Seq[E], offset, step I) iter.Seq[E] {
+	if offset < 0 {
+		panic("offset cannot be negative")
+	}
+	if step <= 0 {
+		panic("step must be positive")
+	}
+	return func(yield func(E) bool) {
+		skip := offset // number of elements to skip before the next yield
+		for e := range seq {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if !yield(e) {
+				return
+			}
+			skip = step - 1
+		}
+	}
+}
+
+// Every returns an iterator over the elements of seq
+// whose indices (starting at 0) satisfy p.
+func Every[I constraints.Integer, E any](seq iter.Seq[E], p func(I) bool) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		var i I
+		for e := range seq {
+			if p(i) && !yield(e) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// TakeLast returns the suffix of seq
+// whose length is min(max(count, 0), Len(seq)).
+// TakeLast retains at most count elements of seq at any given time
+// and yields nothing until seq is exhausted;
+// it only yields elements if seq is finite.
+// See also [LastN].
+func TakeLast[I constraints.Integer, E any](seq iter.Seq[E], count I) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, e := range LastN(seq, count) {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// DropLast returns the prefix of seq
+// that excludes the last min(max(count, 0), Len(seq)) elements.
+// DropLast retains at most count elements of seq at any given time,
+// which delays each element of seq by count elements.
+func DropLast[I constraints.Integer, E any](seq iter.Seq[E], count I) iter.Seq[E] {
+	if count <= 0 {
+		return seq
+	}
+	return func(yield func(E) bool) {
+		var r ring[E]
+		for e := range seq {
+			if uint64(len(r.buf)) < uint64(count) {
+				r.buf = append(r.buf, e)
+				continue
+			}
+			if !yield(r.push(e)) {
+				return
+			}
+		}
+	}
+}
+
+// A ring is a full ring buffer.
+type ring[E any] struct {
+	buf  []E
+	head int // index of the oldest element
+}
+
+// push replaces the oldest element of r by e and returns the former.
+func (r *ring[E]) push(e E) E {
+	old := r.buf[r.head]
+	r.buf[r.head] = e
+	r.head = (r.head + 1) % len(r.buf)
+	return old
+}
+
 // Zip zips seq1 and seq2 into a sequence of corresponding pairs.
 func Zip[K, V any](seq1 iter.Seq[K], seq2 iter.Seq[V]) iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
@@ -171,6 +271,100 @@ func ZipWith[A, B, C any](seq1 iter.Seq[A], seq2 iter.Seq[B], f func(A, B) C) it
 	}
 }
 
+// Intersperse returns an iterator composed of the elements of seq
+// separated by sep.
+func Intersperse[E any](seq iter.Seq[E], sep E) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		var notFirst bool
+		for e := range seq {
+			if notFirst && !yield(sep) {
+				return
+			}
+			if !yield(e) {
+				return
+			}
+			notFirst = true
+		}
+	}
+}
+
+// IntersperseFunc returns an iterator composed of the elements of seq
+// separated by sep(prev, next), where prev and next are the elements
+// of seq that precede and follow the separator, respectively.
+func IntersperseFunc[E any](seq iter.Seq[E], sep func(prev, next E) E) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		var (
+			prev     E
+			notFirst bool
+		)
+		for e := range seq {
+			if notFirst && !yield(sep(prev, e)) {
+				return
+			}
+			if !yield(e) {
+				return
+			}
+			prev = e
+			notFirst = true
+		}
+	}
+}
+
+// Interleave returns an iterator that alternately yields
+// an element of each of seqs, in order, and ends as soon as
+// one of seqs is exhausted; see also [RoundRobin].
+func Interleave[E any](seqs ...iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if len(seqs) == 0 {
+			return
+		}
+		nexts := make([]func() (E, bool), len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			nexts[i] = next
+		}
+		for {
+			for _, next := range nexts {
+				e, ok := next()
+				if !ok || !yield(e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// RoundRobin returns an iterator that alternately yields
+// an element of each of seqs, in order, skipping the ones that
+// have been exhausted, until all of them have been exhausted;
+// see also [Interleave].
+func RoundRobin[E any](seqs ...iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		active := make([]func() (E, bool), len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			active[i] = next
+		}
+		for len(active) > 0 {
+			n := 0 // number of inputs still active after this round
+			for _, next := range active {
+				e, ok := next()
+				if !ok {
+					continue
+				}
+				if !yield(e) {
+					return
+				}
+				active[n] = next
+				n++
+			}
+			active = active[:n]
+		}
+	}
+}
+
 // Filter returns an iterator composed of the pairs of seq that
 // satisfy predicate p.
 func Filter2[K, V any](seq iter.Seq2[K, V], p func(K, V) bool) iter.Seq2[K, V] {
@@ -215,3 +409,38 @@ func Right[K, V any](seq iter.Seq2[K, V]) iter.Seq[V] {
 		}
 	}
 }
+
+// SplitOn returns an iterator over the sub-sequences of seq
+// separated by (non-overlapping) occurrences of the elements of delim,
+// in order and contiguously, much like [strings.Split] does for strings.
+// If delim is empty, SplitOn splits seq after each element.
+// SplitOn collects the elements of delim once, when it's called,
+// and relies on the same algorithm as [IndexSeq];
+// each yielded sub-sequence is a fresh slice.
+func SplitOn[E comparable](seq, delim iter.Seq[E]) iter.Seq[[]E] {
+	proto := newMatcher(slices.Collect(delim), equal)
+	n := len(proto.pattern)
+	if n == 0 {
+		return func(yield func([]E) bool) {
+			for e := range seq {
+				if !yield([]E{e}) {
+					return
+				}
+			}
+		}
+	}
+	return func(yield func([]E) bool) {
+		m := *proto // so that the resulting iterator be re-iterable
+		var chunk []E
+		for e := range seq {
+			chunk = append(chunk, e)
+			if m.advance(e) {
+				if !yield(chunk[:len(chunk)-n]) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		yield(chunk)
+	}
+}
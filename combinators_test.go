@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"iter"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/iterutiltest"
 )
 
 func ExampleEnumerate() {
@@ -293,6 +295,38 @@ func ExampleTakeWhile() {
 	// bar
 }
 
+func TestTakeWhile(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []string
+		p         func(string) bool
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "no break",
+			elems:     []string{"one", "two", "three", "four"},
+			p:         func(s string) bool { return len(s) == 3 },
+			breakWhen: alwaysFalse[string],
+			want:      []string{"one", "two"},
+		}, {
+			desc:      "break early",
+			elems:     []string{"one", "two", "three", "four"},
+			p:         func(s string) bool { return len(s) == 3 },
+			breakWhen: equal("two"),
+			want:      []string{"one"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			got := iterutil.TakeWhile(seq, tc.p)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
 func ExampleDropWhile() {
 	seq := slices.Values([]string{"foo", "bar", "baz", "qux"})
 	isNotBaz := func(s string) bool { return s != "baz" }
@@ -783,3 +817,547 @@ func TestRight(t *testing.T) {
 		t.Run(tc.desc, f)
 	}
 }
+
+func ExampleSplitOn() {
+	// a stream of log lines, with each record terminated by two empty lines
+	lines := iterutil.SeqOf("a", "b", "", "", "c", "", "d", "", "")
+	for record := range iterutil.SplitOn(lines, iterutil.SeqOf("", "")) {
+		fmt.Printf("%q\n", record)
+	}
+	// Output:
+	// ["a" "b"]
+	// ["c" "" "d"]
+	// []
+}
+
+func TestSplitOn(t *testing.T) {
+	cases := []struct {
+		desc      string
+		s         string
+		sep       string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "empty",
+			s:         "",
+			sep:       ",",
+			breakWhen: alwaysFalse[string],
+			want:      []string{""},
+		}, {
+			desc:      "empty separator",
+			s:         "abc",
+			sep:       "",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "b", "c"},
+		}, {
+			desc:      "no occurrence",
+			s:         "abc",
+			sep:       "d",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"abc"},
+		}, {
+			desc:      "leading and trailing separators",
+			s:         "--a--b----",
+			sep:       "--",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"", "a", "b", "", ""},
+		}, {
+			desc:      "overlapping candidates",
+			s:         "aaabaab",
+			sep:       "aab",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "", ""},
+		}, {
+			desc:      "separator with border",
+			s:         "xabaabaaby",
+			sep:       "abaab",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"x", "aaby"},
+		}, {
+			desc:      "break early",
+			s:         "a,b,c",
+			sep:       ",",
+			breakWhen: equal("b"),
+			want:      []string{"a"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := iterutil.SplitOn(slices.Values([]byte(tc.s)), slices.Values([]byte(tc.sep)))
+			got := iterutil.Map(seq, func(b []byte) string { return string(b) })
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func FuzzSplitOn(f *testing.F) {
+	f.Add("", "")
+	f.Add("a,b,c", ",")
+	f.Add("aaabaab", "aab")
+	f.Fuzz(func(t *testing.T, s, sep string) {
+		seq := iterutil.SplitOn(slices.Values([]byte(s)), slices.Values([]byte(sep)))
+		got := iterutil.Map(seq, func(b []byte) string { return string(b) })
+		var want []string
+		if sep == "" {
+			for i := range len(s) {
+				want = append(want, s[i:i+1])
+			}
+		} else {
+			want = strings.Split(s, sep)
+		}
+		iterutiltest.AssertSeq(t, got, want)
+	})
+}
+
+func ExampleTakeLast() {
+	seq := slices.Values([]string{"foo", "bar", "baz", "qux"})
+	for s := range iterutil.TakeLast(seq, 2) {
+		fmt.Println(s)
+	}
+	// Output:
+	// baz
+	// qux
+}
+
+func TestTakeLast(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []int
+		count     int
+		breakWhen func(int) bool
+		want      []int
+	}{
+		{
+			desc:      "empty",
+			count:     2,
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "negative count",
+			elems:     []int{1, 2, 3},
+			count:     -1,
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "zero count",
+			elems:     []int{1, 2, 3},
+			count:     0,
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "count less than length",
+			elems:     []int{1, 2, 3, 4, 5},
+			count:     3,
+			breakWhen: alwaysFalse[int],
+			want:      []int{3, 4, 5},
+		}, {
+			desc:      "count greater than length",
+			elems:     []int{1, 2, 3},
+			count:     5,
+			breakWhen: alwaysFalse[int],
+			want:      []int{1, 2, 3},
+		}, {
+			desc:      "break early",
+			elems:     []int{1, 2, 3, 4, 5},
+			count:     3,
+			breakWhen: equal(4),
+			want:      []int{3},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.TakeLast(slices.Values(tc.elems), tc.count)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleDropLast() {
+	seq := slices.Values([]string{"foo", "bar", "baz", "qux"})
+	for s := range iterutil.DropLast(seq, 2) {
+		fmt.Println(s)
+	}
+	// Output:
+	// foo
+	// bar
+}
+
+func TestDropLast(t *testing.T) {
+	cases := []struct {
+		desc      string
+		seq       iter.Seq[int]
+		count     int
+		breakWhen func(int) bool
+		want      []int
+	}{
+		{
+			desc:      "empty",
+			seq:       iterutil.Empty[int](),
+			count:     2,
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "negative count",
+			seq:       iterutil.SeqOf(1, 2, 3),
+			count:     -1,
+			breakWhen: alwaysFalse[int],
+			want:      []int{1, 2, 3},
+		}, {
+			desc:      "count less than length",
+			seq:       iterutil.SeqOf(1, 2, 3, 4, 5),
+			count:     3,
+			breakWhen: alwaysFalse[int],
+			want:      []int{1, 2},
+		}, {
+			desc:      "count greater than length",
+			seq:       iterutil.SeqOf(1, 2, 3),
+			count:     5,
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "infinite",
+			seq:       iterutil.Iterate(0, func(i int) int { return i + 1 }),
+			count:     3,
+			breakWhen: equal(4),
+			want:      []int{0, 1, 2, 3},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.DropLast(tc.seq, tc.count)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, iterutil.Take(got, 10))
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleStepBy() {
+	naturals := iterutil.Iterate(0, func(i int) int { return i + 1 })
+	for i := range iterutil.Take(iterutil.StepBy(naturals, 3), 4) {
+		fmt.Println(i)
+	}
+	// Output:
+	// 0
+	// 3
+	// 6
+	// 9
+}
+
+func ExampleStride() {
+	seq := slices.Values([]string{"a", "b", "c", "d", "e", "f", "g"})
+	for s := range iterutil.Stride(seq, 1, 2) {
+		fmt.Println(s)
+	}
+	// Output:
+	// b
+	// d
+	// f
+}
+
+func TestStride(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []int
+		offset    int
+		step      int
+		breakWhen func(int) bool
+		want      []int
+		wantPanic any
+	}{
+		{
+			desc:      "empty",
+			offset:    0,
+			step:      2,
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "step of one",
+			elems:     []int{0, 1, 2, 3},
+			offset:    0,
+			step:      1,
+			breakWhen: alwaysFalse[int],
+			want:      []int{0, 1, 2, 3},
+		}, {
+			desc:      "no offset",
+			elems:     []int{0, 1, 2, 3, 4, 5, 6},
+			offset:    0,
+			step:      3,
+			breakWhen: alwaysFalse[int],
+			want:      []int{0, 3, 6},
+		}, {
+			desc:      "offset",
+			elems:     []int{0, 1, 2, 3, 4, 5, 6},
+			offset:    2,
+			step:      3,
+			breakWhen: alwaysFalse[int],
+			want:      []int{2, 5},
+		}, {
+			desc:      "offset beyond length",
+			elems:     []int{0, 1, 2},
+			offset:    3,
+			step:      1,
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "break early",
+			elems:     []int{0, 1, 2, 3, 4, 5, 6},
+			offset:    1,
+			step:      2,
+			breakWhen: equal(5),
+			want:      []int{1, 3},
+		}, {
+			desc:      "negative offset",
+			offset:    -1,
+			step:      1,
+			wantPanic: "offset cannot be negative",
+		}, {
+			desc:      "zero step",
+			offset:    0,
+			step:      0,
+			wantPanic: "step must be positive",
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			if tc.wantPanic != nil {
+				defer func() {
+					if r := recover(); r != tc.wantPanic {
+						t.Errorf("got panic %v; want %v", r, tc.wantPanic)
+					}
+				}()
+			}
+			got := iterutil.Stride(seq, tc.offset, tc.step)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+			if tc.offset == 0 {
+				assertEqual(t, iterutil.StepBy(seq, tc.step), tc.want, tc.breakWhen)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleEvery() {
+	seq := slices.Values([]string{"a", "b", "c", "d", "e", "f", "g"})
+	isPowerOfTwo := func(i uint) bool { return i&(i-1) == 0 && i != 0 }
+	for s := range iterutil.Every(seq, isPowerOfTwo) {
+		fmt.Println(s)
+	}
+	// Output:
+	// b
+	// c
+	// e
+}
+
+func TestEvery(t *testing.T) {
+	isOdd := func(i int) bool { return i%2 != 0 }
+	cases := []struct {
+		desc      string
+		elems     []string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "empty",
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "no break",
+			elems:     []string{"a", "b", "c", "d", "e"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"b", "d"},
+		}, {
+			desc:      "break early",
+			elems:     []string{"a", "b", "c", "d", "e"},
+			breakWhen: equal("d"),
+			want:      []string{"b"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.Every(slices.Values(tc.elems), isOdd)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleIntersperse() {
+	seq := slices.Values([]string{"foo", "bar", "baz"})
+	for s := range iterutil.Intersperse(seq, "|") {
+		fmt.Print(s)
+	}
+	fmt.Println()
+	// Output:
+	// foo|bar|baz
+}
+
+func TestIntersperse(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "empty",
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "one element",
+			elems:     []string{"foo"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"foo"},
+		}, {
+			desc:      "several elements",
+			elems:     []string{"foo", "bar", "baz"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"foo", "|", "bar", "|", "baz"},
+		}, {
+			desc:      "break on separator",
+			elems:     []string{"foo", "bar", "baz"},
+			breakWhen: equal("|"),
+			want:      []string{"foo"},
+		}, {
+			desc:      "break on element",
+			elems:     []string{"foo", "bar", "baz"},
+			breakWhen: equal("bar"),
+			want:      []string{"foo", "|"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.Intersperse(slices.Values(tc.elems), "|")
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleIntersperseFunc() {
+	seq := slices.Values([]int{1, 3, 4, 8})
+	gap := func(prev, next int) int { return next - prev }
+	fmt.Println(slices.Collect(iterutil.IntersperseFunc(seq, gap)))
+	// Output:
+	// [1 2 3 1 4 4 8]
+}
+
+func TestIntersperseFunc(t *testing.T) {
+	sum := func(prev, next int) int { return prev + next }
+	cases := []struct {
+		desc      string
+		elems     []int
+		breakWhen func(int) bool
+		want      []int
+	}{
+		{
+			desc:      "empty",
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:      "one element",
+			elems:     []int{1},
+			breakWhen: alwaysFalse[int],
+			want:      []int{1},
+		}, {
+			desc:      "several elements",
+			elems:     []int{1, 10, 100},
+			breakWhen: alwaysFalse[int],
+			want:      []int{1, 11, 10, 110, 100},
+		}, {
+			desc:      "break early",
+			elems:     []int{1, 10, 100},
+			breakWhen: equal(110),
+			want:      []int{1, 11, 10},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.IntersperseFunc(slices.Values(tc.elems), sum)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleInterleave() {
+	seq1 := slices.Values([]string{"a", "b", "c"})
+	seq2 := slices.Values([]string{"1", "2"})
+	fmt.Println(slices.Collect(iterutil.Interleave(seq1, seq2)))
+	// Output:
+	// [a 1 b 2 c]
+}
+
+func ExampleRoundRobin() {
+	seq1 := slices.Values([]string{"a", "b", "c"})
+	seq2 := slices.Values([]string{"1"})
+	seq3 := slices.Values([]string{"x", "y"})
+	fmt.Println(slices.Collect(iterutil.RoundRobin(seq1, seq2, seq3)))
+	// Output:
+	// [a 1 x b y c]
+}
+
+func TestInterleaveRoundRobin(t *testing.T) {
+	cases := []struct {
+		desc           string
+		elems          [][]int
+		breakWhen      func(int) bool
+		wantInterleave []int
+		wantRoundRobin []int
+	}{
+		{
+			desc:      "no inputs",
+			breakWhen: alwaysFalse[int],
+		}, {
+			desc:           "one input",
+			elems:          [][]int{{1, 2, 3}},
+			breakWhen:      alwaysFalse[int],
+			wantInterleave: []int{1, 2, 3},
+			wantRoundRobin: []int{1, 2, 3},
+		}, {
+			desc:           "empty input first",
+			elems:          [][]int{{}, {1, 2}},
+			breakWhen:      alwaysFalse[int],
+			wantRoundRobin: []int{1, 2},
+		}, {
+			desc:           "same lengths",
+			elems:          [][]int{{1, 2}, {10, 20}, {100, 200}},
+			breakWhen:      alwaysFalse[int],
+			wantInterleave: []int{1, 10, 100, 2, 20, 200},
+			wantRoundRobin: []int{1, 10, 100, 2, 20, 200},
+		}, {
+			desc:           "different lengths",
+			elems:          [][]int{{1, 2, 3}, {10}, {100, 200}},
+			breakWhen:      alwaysFalse[int],
+			wantInterleave: []int{1, 10, 100, 2},
+			wantRoundRobin: []int{1, 10, 100, 2, 200, 3},
+		}, {
+			desc:           "break early",
+			elems:          [][]int{{1, 2, 3}, {10}, {100, 200}},
+			breakWhen:      equal(2),
+			wantInterleave: []int{1, 10, 100},
+			wantRoundRobin: []int{1, 10, 100},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var seqs []iter.Seq[int]
+			for _, elems := range tc.elems {
+				seqs = append(seqs, slices.Values(elems))
+			}
+			got := iterutil.Interleave(seqs...)
+			assertEqual(t, got, tc.wantInterleave, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+			got = iterutil.RoundRobin(seqs...)
+			assertEqual(t, got, tc.wantRoundRobin, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestRoundRobinInfinite(t *testing.T) {
+	naturals := iterutil.Iterate(0, func(i int) int { return i + 1 })
+	got := iterutil.Take(iterutil.RoundRobin(iterutil.SeqOf(-1, -2), naturals), 6)
+	iterutiltest.AssertSeq(t, got, []int{-1, 0, -2, 1, 2, 3})
+}
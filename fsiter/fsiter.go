@@ -0,0 +1,188 @@
+// Package fsiter provides iterators over file systems
+// as abstracted by package [io/fs].
+package fsiter
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/jub0bs/iterutil/internal"
+)
+
+// WalkDir returns an iterator over the paths of the files and directories
+// in the file tree rooted at root, along with their directory entries,
+// in lexical order; see [fs.WalkDir] for details.
+//
+// If onErr is not nil, WalkDir calls onErr when it cannot read root or one of
+// the directories in the tree: if onErr returns true,
+// the walk skips the offending directory and continues;
+// otherwise, the walk stops.
+// If onErr is nil, WalkDir silently skips the offending directories.
+func WalkDir(fsys fs.FS, root string, onErr func(path string, err error) bool) iter.Seq2[string, fs.DirEntry] {
+	return func(yield func(string, fs.DirEntry) bool) {
+		fn := func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if onErr != nil && !onErr(path, err) {
+					return fs.SkipAll
+				}
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !yield(path, d) {
+				return fs.SkipAll
+			}
+			return nil
+		}
+		fs.WalkDir(fsys, root, fn) // all errors are handled by fn
+	}
+}
+
+// ReadDir reads the directory named name in fsys and
+// returns an iterator over its entries ordered by filename.
+// If fsys implements [fs.ReadDirFS], whose implementations already sort
+// their results, ReadDir behaves like [fs.ReadDir];
+// otherwise, contrary to [fs.ReadDir], it sorts the entries lazily:
+// see [github.com/jub0bs/iterutil.SortedFromMap].
+func ReadDir(fsys fs.FS, name string) (iter.Seq[fs.DirEntry], error) {
+	if fsys, ok := fsys.(fs.ReadDirFS); ok {
+		// Implementations of fs.ReadDirFS sort their results by filename.
+		des, err := fsys.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return slices.Values(des), nil
+	}
+	return ReadDirFunc(fsys, name, compareNames)
+}
+
+func compareNames(de1, de2 fs.DirEntry) int {
+	return strings.Compare(de1.Name(), de2.Name())
+}
+
+// ReadDirFunc reads the directory named name in fsys and
+// returns an iterator over its entries ordered by cmp.
+// ReadDirFunc sorts the entries lazily:
+// see [github.com/jub0bs/iterutil.SortedFromMapFunc].
+//
+// Note that, for a deterministic behavior,
+// cmp must define a [total order] on directory entries.
+//
+// [total order]: https://en.wikipedia.org/wiki/Total_order
+func ReadDirFunc(fsys fs.FS, name string, cmp func(fs.DirEntry, fs.DirEntry) int) (iter.Seq[fs.DirEntry], error) {
+	des, err := readDir(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	seq := func(yield func(fs.DirEntry) bool) {
+		// The heap reorders the slice that it is given;
+		// cloning des keeps the resulting iterator re-iterable.
+		internal.NewHeapFunc(slices.Clone(des), cmp).Iterator(yield)
+	}
+	return seq, nil
+}
+
+// readDir reads the directory named name in fsys
+// and returns its entries, in no particular order.
+func readDir(fsys fs.FS, name string) ([]fs.DirEntry, error) {
+	if fsys, ok := fsys.(fs.ReadDirFS); ok {
+		return fsys.ReadDir(name)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir, ok := f.(fs.ReadDirFile)
+	if !ok {
+		err := errors.New("not implemented")
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return dir.ReadDir(-1)
+}
+
+// Glob, if pattern is well-formed, returns an iterator over the names of
+// all files matching pattern, in lexical order, and a nil error;
+// otherwise, it returns a nil iterator and [path.ErrBadPattern].
+// The syntax of patterns is the same as in [path.Match].
+// Contrary to [fs.Glob], Glob reads directories lazily,
+// only as far as the resulting iterator gets consumed.
+// Like [fs.Glob], Glob ignores I/O errors.
+func Glob(fsys fs.FS, pattern string) (iter.Seq[string], error) {
+	// Check pattern is well-formed.
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	seq := func(yield func(string) bool) {
+		glob(fsys, pattern, yield)
+	}
+	return seq, nil
+}
+
+// glob passes all names matching pattern to yield
+// and reports whether iteration should continue.
+// It draws heavy inspiration from fs.Glob's implementation.
+func glob(fsys fs.FS, pattern string, yield func(string) bool) bool {
+	if !hasMeta(pattern) {
+		if _, err := fs.Stat(fsys, pattern); err != nil {
+			return true
+		}
+		return yield(pattern)
+	}
+	dir, file := path.Split(pattern)
+	dir = cleanGlobPath(dir)
+	if !hasMeta(dir) {
+		return globDir(fsys, dir, file, yield)
+	}
+	// Prevent infinite recursion; see https://go.dev/issue/15879.
+	if dir == pattern {
+		return true
+	}
+	cont := true
+	glob(fsys, dir, func(d string) bool {
+		cont = globDir(fsys, d, file, yield)
+		return cont
+	})
+	return cont
+}
+
+// globDir passes to yield the names of the entries of dir matching pattern
+// and reports whether iteration should continue.
+func globDir(fsys fs.FS, dir, pattern string, yield func(string) bool) bool {
+	des, err := ReadDir(fsys, dir)
+	if err != nil {
+		return true // ignore I/O error
+	}
+	for de := range des {
+		name := de.Name()
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false // cannot happen: pattern was checked upfront
+		}
+		if matched && !yield(path.Join(dir, name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanGlobPath prepares path for glob matching.
+func cleanGlobPath(path string) string {
+	switch path {
+	case "":
+		return "."
+	default:
+		return path[0 : len(path)-1] // chop off trailing separator
+	}
+}
+
+// hasMeta reports whether path contains any of the magic characters
+// recognized by path.Match.
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
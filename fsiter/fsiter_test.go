@@ -0,0 +1,321 @@
+package fsiter_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/fsiter"
+)
+
+var fsys = fstest.MapFS{
+	"go.mod":               {},
+	"README.md":            {},
+	"cmd/app/main.go":      {},
+	"cmd/app/main_test.go": {},
+	"cmd/tool/main.go":     {},
+	"internal/util.go":     {},
+	"internal/doc.go":      {},
+}
+
+// openOnly hides all the methods of the underlying file system but Open.
+type openOnly struct {
+	fs.FS
+}
+
+// faultyFS fails to read the directory named bad.
+type faultyFS struct {
+	fstest.MapFS
+	bad string
+}
+
+var errBoom = errors.New("boom")
+
+func (fsys faultyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name == fsys.bad {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errBoom}
+	}
+	return fsys.MapFS.ReadDir(name)
+}
+
+func ExampleWalkDir() {
+	isGoFile := func(p string) bool { return path.Ext(p) == ".go" }
+	paths := iterutil.Left(fsiter.WalkDir(fsys, ".", nil))
+	for p := range iterutil.Take(iterutil.Filter(paths, isGoFile), 3) {
+		fmt.Println(p)
+	}
+	// Output:
+	// cmd/app/main.go
+	// cmd/app/main_test.go
+	// cmd/tool/main.go
+}
+
+func TestWalkDir(t *testing.T) {
+	all := []string{
+		".",
+		"README.md",
+		"cmd",
+		"cmd/app",
+		"cmd/app/main.go",
+		"cmd/app/main_test.go",
+		"cmd/tool",
+		"cmd/tool/main.go",
+		"go.mod",
+		"internal",
+		"internal/doc.go",
+		"internal/util.go",
+	}
+	cases := []struct {
+		desc      string
+		fsys      fs.FS
+		root      string
+		stopOnErr bool
+		want      []string
+		wantErrs  []string
+	}{
+		{
+			desc: "whole tree",
+			fsys: fsys,
+			root: ".",
+			want: all,
+		}, {
+			desc: "subtree",
+			fsys: fsys,
+			root: "cmd/app",
+			want: []string{"cmd/app", "cmd/app/main.go", "cmd/app/main_test.go"},
+		}, {
+			desc:     "nonexistent root",
+			fsys:     fsys,
+			root:     "nonexistent",
+			wantErrs: []string{"nonexistent"},
+		}, {
+			desc: "skip unreadable directory",
+			fsys: faultyFS{fsys, "cmd/app"},
+			root: ".",
+			want: slices.DeleteFunc(slices.Clone(all), func(p string) bool {
+				return strings.HasPrefix(p, "cmd/app/")
+			}),
+			wantErrs: []string{"cmd/app"},
+		}, {
+			desc:      "stop at unreadable directory",
+			fsys:      faultyFS{fsys, "cmd/app"},
+			root:      ".",
+			stopOnErr: true,
+			want:      []string{".", "README.md", "cmd", "cmd/app"},
+			wantErrs:  []string{"cmd/app"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var gotErrs []string
+			onErr := func(p string, err error) bool {
+				gotErrs = append(gotErrs, p)
+				return !tc.stopOnErr
+			}
+			var got []string
+			for p, d := range fsiter.WalkDir(tc.fsys, tc.root, onErr) {
+				if path.Base(p) != d.Name() && p != "." {
+					t.Errorf("path %q does not match entry name %q", p, d.Name())
+				}
+				got = append(got, p)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+			if !slices.Equal(gotErrs, tc.wantErrs) {
+				t.Errorf("got errors for %q; want errors for %q", gotErrs, tc.wantErrs)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestWalkDirEarlyBreak(t *testing.T) {
+	var got []string
+	for p := range fsiter.WalkDir(fsys, ".", nil) {
+		if p == "cmd/tool" {
+			break
+		}
+		got = append(got, p)
+	}
+	want := []string{".", "README.md", "cmd", "cmd/app", "cmd/app/main.go", "cmd/app/main_test.go"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func ExampleReadDir() {
+	des, err := fsiter.ReadDir(fsys, ".")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for de := range des {
+		fmt.Println(de.Name(), de.IsDir())
+	}
+	// Output:
+	// README.md false
+	// cmd true
+	// go.mod false
+	// internal true
+}
+
+func TestReadDir(t *testing.T) {
+	cases := []struct {
+		desc    string
+		fsys    fs.FS
+		name    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			desc: "ReadDirFS",
+			fsys: fsys,
+			name: "cmd/app",
+			want: []string{"main.go", "main_test.go"},
+		}, {
+			desc: "not ReadDirFS",
+			fsys: openOnly{fsys},
+			name: ".",
+			want: []string{"README.md", "cmd", "go.mod", "internal"},
+		}, {
+			desc:    "ReadDirFS nonexistent",
+			fsys:    fsys,
+			name:    "nonexistent",
+			wantErr: true,
+		}, {
+			desc:    "not ReadDirFS nonexistent",
+			fsys:    openOnly{fsys},
+			name:    "nonexistent",
+			wantErr: true,
+		}, {
+			desc:    "not a directory",
+			fsys:    openOnly{fsys},
+			name:    "go.mod",
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			des, err := fsiter.ReadDir(tc.fsys, tc.name)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v; want error: %t", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			name := func(de fs.DirEntry) string { return de.Name() }
+			// range twice to check that the iterator is re-iterable
+			for range 2 {
+				got := slices.Collect(iterutil.Map(des, name))
+				if !slices.Equal(got, tc.want) {
+					t.Errorf("got %q; want %q", got, tc.want)
+				}
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleReadDirFunc() {
+	dirsFirst := func(de1, de2 fs.DirEntry) int {
+		if de1.IsDir() != de2.IsDir() {
+			if de1.IsDir() {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(de1.Name(), de2.Name())
+	}
+	des, err := fsiter.ReadDirFunc(fsys, ".", dirsFirst)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for de := range des {
+		fmt.Println(de.Name())
+	}
+	// Output:
+	// cmd
+	// internal
+	// README.md
+	// go.mod
+}
+
+func ExampleGlob() {
+	matches, err := fsiter.Glob(fsys, "cmd/*/main*.go")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for m := range matches {
+		fmt.Println(m)
+	}
+	// Output:
+	// cmd/app/main.go
+	// cmd/app/main_test.go
+	// cmd/tool/main.go
+}
+
+func TestGlob(t *testing.T) {
+	patterns := []string{
+		"go.mod",
+		"nonexistent",
+		"*",
+		"*.md",
+		"*/*.go",
+		"*/*/*.go",
+		"cmd/*",
+		"cmd/*/main.go",
+		"*/app/*",
+		"[a-h]*",
+		"internal/[!u]*",
+		"cmd/app/main?go",
+		"*/nonexistent/*",
+	}
+	for _, pattern := range patterns {
+		f := func(t *testing.T) {
+			want, err := fs.Glob(fsys, pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matches, err := fsiter.Glob(openOnly{fsys}, pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := slices.Collect(matches)
+			if !slices.Equal(got, want) {
+				t.Errorf("got %q; want %q", got, want)
+			}
+			// early break
+			for i := range want {
+				var got []string
+				for m := range matches {
+					if len(got) == i {
+						break
+					}
+					got = append(got, m)
+				}
+				if !slices.Equal(got, want[:i]) {
+					t.Errorf("got %q; want %q", got, want[:i])
+				}
+			}
+		}
+		t.Run(pattern, f)
+	}
+}
+
+func TestGlobBadPattern(t *testing.T) {
+	for _, pattern := range []string{"[", "cmd/[", "*/["} {
+		matches, err := fsiter.Glob(fsys, pattern)
+		if !errors.Is(err, path.ErrBadPattern) || matches != nil {
+			t.Errorf("%q: got %v, %v; want nil, %v", pattern, matches, err, path.ErrBadPattern)
+		}
+	}
+}
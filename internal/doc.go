@@ -1,3 +1,4 @@
 // Package internal contains two implementations of a binary heap,
-// both of which draw heavy inspiration from package [container/heap].
+// both of which draw heavy inspiration from package [container/heap],
+// as well as algorithms for computing exact and approximate quantiles.
 package internal
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// A GK is a [Greenwald-Khanna] summary, which answers quantile queries
+// over a stream of values with bounded rank error
+// while retaining only a small fraction of those values.
+//
+// [Greenwald-Khanna]: https://doi.org/10.1145/375663.375670
+type GK[T cmp.Ordered] struct {
+	eps    float64
+	n      int // number of inserted values
+	tuples []gkTuple[T]
+}
+
+// A gkTuple summarizes the values of a GK near v.
+// The (unknown) rank of v lies between rmin and rmin+delta,
+// where rmin is the sum of the g fields of all tuples up to this one.
+type gkTuple[T any] struct {
+	v     T
+	g     int
+	delta int
+}
+
+// NewGK returns an empty summary whose queries are accurate up to eps*n
+// in rank, where n is the number of values inserted into the summary;
+// eps must be in the range (0, 1).
+func NewGK[T cmp.Ordered](eps float64) *GK[T] {
+	return &GK[T]{eps: eps}
+}
+
+// Len returns the number of values inserted into s.
+func (s *GK[T]) Len() int {
+	return s.n
+}
+
+// Insert inserts v into s.
+func (s *GK[T]) Insert(v T) {
+	if period := int(1 / (2 * s.eps)); period > 0 && s.n%period == 0 {
+		s.compress()
+	}
+	// index of the first tuple whose value is greater than v
+	i, _ := slices.BinarySearchFunc(s.tuples, v, func(t gkTuple[T], v T) int {
+		if cmp.Less(v, t.v) {
+			return 1
+		}
+		return -1
+	})
+	var delta int
+	if 0 < i && i < len(s.tuples) { // neither a new minimum nor a new maximum
+		delta = max(s.threshold()-1, 0)
+	}
+	s.tuples = slices.Insert(s.tuples, i, gkTuple[T]{v, 1, delta})
+	s.n++
+}
+
+// threshold returns the upper bound of g+delta for every tuple of s.
+func (s *GK[T]) threshold() int {
+	return int(math.Floor(2 * s.eps * float64(s.n)))
+}
+
+// compress merges adjacent tuples whenever doing so preserves
+// the invariant that g+delta does not exceed the threshold.
+// The first and last tuples, which record the exact minimum and maximum,
+// are never merged away.
+func (s *GK[T]) compress() {
+	threshold := s.threshold()
+	for i := len(s.tuples) - 2; i >= 1; i-- {
+		t, next := s.tuples[i], &s.tuples[i+1]
+		if t.g+next.g+next.delta <= threshold {
+			next.g += t.g
+			s.tuples = slices.Delete(s.tuples, i, i+1)
+		}
+	}
+}
+
+// Query returns a value whose rank, among the values inserted into s,
+// differs from ceil(q*n) by at most eps*n, where n is s.Len();
+// s must not be empty, and q must be in the range [0, 1].
+func (s *GK[T]) Query(q float64) T {
+	r := max(math.Ceil(q*float64(s.n)), 1)
+	slack := s.eps * float64(s.n)
+	var rmin int
+	for _, t := range s.tuples {
+		rmin += t.g
+		rmax := rmin + t.delta
+		if r-float64(rmin) <= slack && float64(rmax)-r <= slack {
+			return t.v
+		}
+	}
+	return s.tuples[len(s.tuples)-1].v
+}
@@ -0,0 +1,45 @@
+package internal_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/jub0bs/iterutil/internal"
+)
+
+func TestGK(t *testing.T) {
+	cases := []struct {
+		desc string
+		eps  float64
+		n    int
+	}{
+		{desc: "few values", eps: 0.1, n: 7},
+		{desc: "coarse", eps: 0.1, n: 10_000},
+		{desc: "fine", eps: 0.01, n: 10_000},
+		{desc: "very fine", eps: 0.001, n: 100_000},
+	}
+	qs := []float64{0, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			s := internal.NewGK[int](tc.eps)
+			// Because the values are a permutation of [1, n],
+			// each value coincides with its rank.
+			for _, v := range rand.Perm(tc.n) {
+				s.Insert(v + 1)
+			}
+			if got := s.Len(); got != tc.n {
+				t.Errorf("got len %d; want %d", got, tc.n)
+			}
+			slack := tc.eps * float64(tc.n)
+			for _, q := range qs {
+				got := s.Query(q)
+				want := max(math.Ceil(q*float64(tc.n)), 1)
+				if math.Abs(float64(got)-want) > slack {
+					t.Errorf("q=%v: got rank %d; want %v ± %v", q, got, want, slack)
+				}
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
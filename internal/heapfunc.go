@@ -75,3 +75,43 @@ func (h HeapFunc[_]) down(i, n int) {
 		i = j
 	}
 }
+
+// Min returns the least element of h, which must not be empty.
+func (h HeapFunc[T]) Min() T {
+	return h.s[0]
+}
+
+// ReplaceMin replaces the least element of h, which must not be empty, by v.
+func (h HeapFunc[T]) ReplaceMin(v T) {
+	h.s[0] = v
+	h.down(0, h.len())
+}
+
+// Len returns the number of elements in h.
+func (h HeapFunc[T]) Len() int {
+	return h.len()
+}
+
+// Push adds v to h and returns the resulting heap.
+func (h HeapFunc[T]) Push(v T) HeapFunc[T] {
+	h.s = append(h.s, v)
+	h.up(h.len() - 1)
+	return h
+}
+
+// Pop removes the least element of h, which must not be empty,
+// and returns it along with the resulting heap.
+func (h HeapFunc[T]) Pop() (T, HeapFunc[T]) {
+	return h.pop()
+}
+
+func (h HeapFunc[_]) up(j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !h.less(j, i) {
+			break
+		}
+		h.swap(i, j)
+		j = i
+	}
+}
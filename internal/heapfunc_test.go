@@ -41,3 +41,43 @@ func TestHeapFunc(t *testing.T) {
 		t.Run(tc.desc, f)
 	}
 }
+
+func TestHeapFuncReplaceMin(t *testing.T) {
+	s := []int{5, 3, 4}
+	h := internal.NewHeapFunc(s, cmp.Compare)
+	// keep the three greatest values
+	for _, v := range []int{1, 6, 2, 7} {
+		if v > h.Min() {
+			h.ReplaceMin(v)
+		}
+	}
+	if got, want := h.Min(), 5; got != want {
+		t.Errorf("got min %d; want %d", got, want)
+	}
+	var got []int
+	for v := range h.Iterator {
+		got = append(got, v)
+	}
+	if want := []int{5, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestHeapFuncPushPop(t *testing.T) {
+	h := internal.NewHeapFunc([]int{5, 3}, cmp.Compare)
+	for _, v := range []int{4, 1, 6, 2} {
+		h = h.Push(v)
+	}
+	if got, want := h.Len(), 6; got != want {
+		t.Errorf("got len %d; want %d", got, want)
+	}
+	var got []int
+	for h.Len() > 0 {
+		var v int
+		v, h = h.Pop()
+		got = append(got, v)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"cmp"
+	"math/rand/v2"
+)
+
+// Select rearranges s so that s[k] is the element that would be at index k
+// if s were sorted, all elements in s[:k] are less than or equal to s[k],
+// and all elements in s[k+1:] are greater than or equal to s[k].
+// k must be in the range [0, len(s)).
+// Select relies on quickselect with random pivots and three-way partitioning,
+// which runs in expected linear time, even if s contains many duplicates.
+// Elements are compared with [cmp.Less].
+func Select[T cmp.Ordered](s []T, k int) {
+	lo, hi := 0, len(s)
+	for hi-lo > 1 {
+		lt, gt := partition(s, lo, hi, lo+rand.IntN(hi-lo))
+		switch {
+		case k < lt:
+			hi = lt
+		case k >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+}
+
+// partition rearranges s[lo:hi] around v := s[pivot]
+// (Dijkstra's three-way scheme, a.k.a. the Dutch national flag)
+// and returns the bounds of the range s[lt:gt] of elements equal to v;
+// the elements in s[lo:lt] are less than v and
+// those in s[gt:hi] are greater than v.
+// Because all elements equal to v end up in s[lt:gt],
+// inputs with many duplicates do not degrade performance.
+func partition[T cmp.Ordered](s []T, lo, hi, pivot int) (lt, gt int) {
+	v := s[pivot]
+	lt, gt = lo, hi
+	for i := lo; i < gt; {
+		switch {
+		case cmp.Less(s[i], v):
+			s[lt], s[i] = s[i], s[lt]
+			lt++
+			i++
+		case cmp.Less(v, s[i]):
+			gt--
+			s[i], s[gt] = s[gt], s[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
@@ -0,0 +1,99 @@
+package internal_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jub0bs/iterutil/internal"
+)
+
+func TestSelect(t *testing.T) {
+	cases := []struct {
+		desc string
+		s    []int
+	}{
+		{
+			desc: "one element",
+			s:    []int{42},
+		}, {
+			desc: "sorted",
+			s:    []int{1, 2, 3, 4, 5, 6},
+		}, {
+			desc: "reversed",
+			s:    []int{6, 5, 4, 3, 2, 1},
+		}, {
+			desc: "duplicates",
+			s:    []int{3, 1, 3, 3, 2, 1, 3},
+		}, {
+			desc: "random",
+			s:    rand.Perm(100),
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			sorted := slices.Sorted(slices.Values(tc.s))
+			for k := range tc.s {
+				s := slices.Clone(tc.s)
+				internal.Select(s, k)
+				if s[k] != sorted[k] {
+					t.Fatalf("k=%d: got %d; want %d", k, s[k], sorted[k])
+				}
+				for _, v := range s[:k] {
+					if v > s[k] {
+						t.Fatalf("k=%d: %d precedes %d", k, v, s[k])
+					}
+				}
+				for _, v := range s[k+1:] {
+					if v < s[k] {
+						t.Fatalf("k=%d: %d follows %d", k, v, s[k])
+					}
+				}
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestSelectManyDuplicates(t *testing.T) {
+	// Quickselect with a two-way partition takes quadratic time
+	// (several seconds for such inputs) if many elements are equal.
+	const (
+		n       = 1 << 17
+		timeout = time.Second
+	)
+	fewDistinct := make([]int, n)
+	for i := range fewDistinct {
+		fewDistinct[i] = rand.IntN(5)
+	}
+	cases := []struct {
+		desc string
+		s    []int
+	}{
+		{
+			desc: "all equal",
+			s:    make([]int, n),
+		}, {
+			desc: "few distinct",
+			s:    fewDistinct,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			sorted := slices.Sorted(slices.Values(tc.s))
+			for _, k := range []int{0, n / 100, n / 2, n - n/100, n - 1} {
+				s := slices.Clone(tc.s)
+				start := time.Now()
+				internal.Select(s, k)
+				if d := time.Since(start); d > timeout {
+					t.Errorf("k=%d: took %v; want at most %v", k, d, timeout)
+				}
+				if s[k] != sorted[k] {
+					t.Errorf("k=%d: got %d; want %d", k, s[k], sorted[k])
+				}
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
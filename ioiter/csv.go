@@ -0,0 +1,195 @@
+package ioiter
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// CSVRecords returns an iterator over the CSV records read from r,
+// as parsed by a [csv.Reader] with default settings.
+// Each record is yielded as a fresh slice,
+// which the caller is free to retain.
+// If reading from r or parsing fails, the iterator yields
+// nil and the resulting error as its last pair.
+func CSVRecords(r io.Reader) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		cr := csv.NewReader(r)
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// CSVStructs, if T is a struct type, returns an iterator over
+// the CSV records read from r, each of which is decoded into a value of type T;
+// otherwise, it panics.
+// The first record read from r is interpreted as a header,
+// whose columns are matched against the exported fields of T:
+// a field is matched to the column named after the field's "csv" tag,
+// if any, or after the field itself otherwise;
+// fields whose tag is "-" are ignored.
+// Columns that match no field are ignored,
+// and fields that match no column are left to their zero value.
+//
+// The supported field types are strings, booleans, integers,
+// floating-point numbers, and types that implement
+// [encoding.TextUnmarshaler] through a pointer receiver.
+// If reading from r, parsing, or decoding fails, the iterator yields
+// the zero value and the resulting error as its last pair.
+func CSVStructs[T any](r io.Reader) iter.Seq2[T, error] {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		panic("type argument must be a struct type")
+	}
+	return func(yield func(T, error) bool) {
+		var zero T
+		cr := csv.NewReader(r)
+		header, err := cr.Read()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		fields, err := csvFields(typ, header)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			var v T
+			rv := reflect.ValueOf(&v).Elem()
+			for i, f := range fields {
+				if f == nil {
+					continue
+				}
+				if err := f.set(rv.Field(f.index), record[i]); err != nil {
+					line, col := cr.FieldPos(i)
+					const tmpl = "ioiter: record on line %d, column %d (%q): %w"
+					yield(zero, fmt.Errorf(tmpl, line, col, header[i], err))
+					return
+				}
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// A csvField describes how to decode a CSV column into a struct field.
+type csvField struct {
+	index int
+	set   func(reflect.Value, string) error
+}
+
+// csvFields returns, for each column in header,
+// the field of typ (a struct type) that it matches, if any, or nil.
+func csvFields(typ reflect.Type, header []string) ([]*csvField, error) {
+	byName := make(map[string]*csvField)
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		set, err := csvSetter(sf.Type)
+		if err != nil {
+			return nil, fmt.Errorf("ioiter: field %s: %w", sf.Name, err)
+		}
+		byName[name] = &csvField{index: i, set: set}
+	}
+	fields := make([]*csvField, len(header))
+	for i, col := range header {
+		fields[i] = byName[col]
+	}
+	return fields, nil
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+func csvSetter(typ reflect.Type) (func(reflect.Value, string) error, error) {
+	if reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return func(v reflect.Value, s string) error {
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}, nil
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return func(v reflect.Value, s string) error {
+			v.SetString(s)
+			return nil
+		}, nil
+	case reflect.Bool:
+		return func(v reflect.Value, s string) error {
+			b, err := strconv.ParseBool(s)
+			v.SetBool(b)
+			return err
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value, s string) error {
+			i, err := strconv.ParseInt(s, 10, typ.Bits())
+			v.SetInt(i)
+			return err
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v reflect.Value, s string) error {
+			u, err := strconv.ParseUint(s, 10, typ.Bits())
+			v.SetUint(u)
+			return err
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value, s string) error {
+			f, err := strconv.ParseFloat(s, typ.Bits())
+			v.SetFloat(f)
+			return err
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+// WriteCSV writes to w the records of seq as CSV,
+// using a [csv.Writer] with default settings.
+// WriteCSV stops at and returns the first error encountered.
+// It terminates if and only if seq is finite.
+func WriteCSV(w io.Writer, seq iter.Seq[[]string]) error {
+	cw := csv.NewWriter(w)
+	for record := range seq {
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,253 @@
+package ioiter_test
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jub0bs/iterutil/ioiter"
+)
+
+func ExampleCSVRecords() {
+	r := strings.NewReader("name,age\nalice,42\n\"bob, jr.\",17\n")
+	for record, err := range ioiter.CSVRecords(r) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("%q\n", record)
+	}
+	// Output:
+	// ["name" "age"]
+	// ["alice" "42"]
+	// ["bob, jr." "17"]
+}
+
+func TestCSVRecords(t *testing.T) {
+	cases := []struct {
+		desc    string
+		r       io.Reader
+		want    []string // records joined with "|"
+		wantErr bool
+	}{
+		{
+			desc: "empty",
+			r:    strings.NewReader(""),
+		}, {
+			desc: "no error",
+			r:    strings.NewReader("a,b\nc,d\n"),
+			want: []string{"a|b", "c|d"},
+		}, {
+			desc:    "wrong number of fields",
+			r:       strings.NewReader("a,b\nc\n"),
+			want:    []string{"a|b"},
+			wantErr: true,
+		}, {
+			desc:    "read error",
+			r:       failingReader("a,b\nc,d\n"),
+			want:    []string{"a|b", "c|d"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var (
+				got    []string
+				gotErr error
+			)
+			for record, err := range ioiter.CSVRecords(tc.r) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, strings.Join(record, "|"))
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+			if (gotErr != nil) != tc.wantErr {
+				t.Errorf("got error %v; want error: %t", gotErr, tc.wantErr)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleCSVStructs() {
+	type user struct {
+		Name  string
+		Age   int    `csv:"age"`
+		Email string `csv:"-"`
+	}
+	r := strings.NewReader("age,Name,Email\n42,alice,alice@example.com\n17,bob,bob@example.com\n")
+	for u, err := range ioiter.CSVStructs[user](r) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("%+v\n", u)
+	}
+	// Output:
+	// {Name:alice Age:42 Email:}
+	// {Name:bob Age:17 Email:}
+}
+
+type record struct {
+	S        string    `csv:"s"`
+	B        bool      `csv:"b"`
+	I        int8      `csv:"i"`
+	U        uint      `csv:"u"`
+	F        float64   `csv:"f"`
+	T        time.Time `csv:"t"`
+	Ignored  int       `csv:"-"`
+	Untagged string
+	private  string
+}
+
+func TestCSVStructs(t *testing.T) {
+	ts := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		desc    string
+		r       io.Reader
+		want    []record
+		wantErr bool
+	}{
+		{
+			desc: "empty",
+			r:    strings.NewReader(""),
+		}, {
+			desc: "header only",
+			r:    strings.NewReader("s,b\n"),
+		}, {
+			desc: "all supported types",
+			r: strings.NewReader("s,b,i,u,f,t,Untagged,-,private,unknown\n" +
+				"foo,true,-3,4,0.5,2025-01-31T12:00:00Z,bar,1,baz,qux\n"),
+			want: []record{
+				{S: "foo", B: true, I: -3, U: 4, F: 0.5, T: ts, Untagged: "bar"},
+			},
+		}, {
+			desc: "missing columns",
+			r:    strings.NewReader("i\n1\n2\n"),
+			want: []record{{I: 1}, {I: 2}},
+		}, {
+			desc:    "integer overflow",
+			r:       strings.NewReader("i\n1\n128\n3\n"),
+			want:    []record{{I: 1}},
+			wantErr: true,
+		}, {
+			desc:    "invalid boolean",
+			r:       strings.NewReader("b\nmaybe\n"),
+			wantErr: true,
+		}, {
+			desc:    "invalid time",
+			r:       strings.NewReader("t\nyesterday\n"),
+			wantErr: true,
+		}, {
+			desc:    "read error",
+			r:       failingReader("i\n1\n"),
+			want:    []record{{I: 1}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var (
+				got    []record
+				gotErr error
+			)
+			for r, err := range ioiter.CSVStructs[record](tc.r) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, r)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %+v; want %+v", got, tc.want)
+			}
+			if (gotErr != nil) != tc.wantErr {
+				t.Errorf("got error %v; want error: %t", gotErr, tc.wantErr)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestCSVStructsUnsupported(t *testing.T) {
+	t.Run("non-struct type", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("got no panic; want panic")
+			}
+		}()
+		ioiter.CSVStructs[int](strings.NewReader(""))
+	})
+	t.Run("unsupported field type", func(t *testing.T) {
+		type unsupported struct {
+			C []string
+		}
+		for _, err := range ioiter.CSVStructs[unsupported](strings.NewReader("C\nfoo\n")) {
+			if err == nil {
+				t.Fatal("got no error; want error")
+			}
+		}
+	})
+}
+
+func ExampleWriteCSV() {
+	records := slices.Values([][]string{
+		{"name", "age"},
+		{"alice", "42"},
+		{"bob, jr.", "17"},
+	})
+	var sb strings.Builder
+	if err := ioiter.WriteCSV(&sb, records); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(sb.String())
+	// Output:
+	// name,age
+	// alice,42
+	// "bob, jr.",17
+}
+
+func TestWriteCSV(t *testing.T) {
+	cases := []struct {
+		desc    string
+		records [][]string
+		w       *limitedWriter
+		want    string
+		wantErr bool
+	}{
+		{
+			desc: "empty",
+			w:    &limitedWriter{n: 100},
+		}, {
+			desc:    "no error",
+			records: [][]string{{"a", "b"}, {"c", "d\ne"}},
+			w:       &limitedWriter{n: 100},
+			want:    "a,b\nc,\"d\ne\"\n",
+		}, {
+			desc:    "write error",
+			records: [][]string{{"a", "b"}, {"c", "d"}},
+			w:       &limitedWriter{n: 3},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			err := ioiter.WriteCSV(tc.w, slices.Values(tc.records))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error %v; want error: %t", err, tc.wantErr)
+			}
+			if got := tc.w.sb.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
@@ -0,0 +1,148 @@
+// Package ioiter provides iterators backed by [io.Reader] values,
+// as well as sinks that write the elements of iterators to [io.Writer] values.
+//
+// Because reading from an [io.Reader] consumes its data
+// and because the iterators provided by this package buffer their reads,
+// those iterators are single-use: you should range over each of them
+// at most once.
+//
+// Those iterators are also fallible: if reading fails
+// for any reason other than [io.EOF],
+// they yield the resulting error as the second component of their last pair.
+package ioiter
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"iter"
+)
+
+// An Option configures an iterator provided by this package.
+type Option func(*config)
+
+type config struct {
+	maxTokenSize int
+}
+
+// MaxTokenSize, if n is positive, sets the maximum size of the tokens
+// yielded by the resulting iterator; otherwise, it panics.
+// The maximum size defaults to [bufio.MaxScanTokenSize].
+// If a token exceeds that size, the iterator yields [bufio.ErrTooLong].
+func MaxTokenSize(n int) Option {
+	if n <= 0 {
+		panic("max token size must be positive")
+	}
+	return func(c *config) {
+		c.maxTokenSize = n
+	}
+}
+
+// SplitFunc returns an iterator over the tokens
+// (as delimited by split) read from r.
+// If reading from r fails, the iterator yields
+// the empty string and the resulting error as its last pair.
+func SplitFunc(r io.Reader, split bufio.SplitFunc, opts ...Option) iter.Seq2[string, error] {
+	cfg := config{
+		maxTokenSize: bufio.MaxScanTokenSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(yield func(string, error) bool) {
+		sc := bufio.NewScanner(r)
+		sc.Split(split)
+		const initialBufSize = 4096
+		sc.Buffer(make([]byte, 0, min(initialBufSize, cfg.maxTokenSize)), cfg.maxTokenSize)
+		for sc.Scan() {
+			if !yield(sc.Text(), nil) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+// Lines returns an iterator over the lines read from r,
+// stripped of any trailing end-of-line marker;
+// see [bufio.ScanLines] for details.
+// If reading from r fails, the iterator yields
+// the empty string and the resulting error as its last pair.
+func Lines(r io.Reader, opts ...Option) iter.Seq2[string, error] {
+	return SplitFunc(r, bufio.ScanLines, opts...)
+}
+
+// Delimited, if sep is not empty, returns an iterator over
+// the records read from r and separated by sep;
+// otherwise, it panics.
+// The records do not contain sep.
+// If the data read from r ends with sep, no empty record is yielded
+// after the last separator.
+// If reading from r fails, the iterator yields
+// the empty string and the resulting error as its last pair.
+func Delimited(r io.Reader, sep string, opts ...Option) iter.Seq2[string, error] {
+	if sep == "" {
+		panic("sep cannot be empty")
+	}
+	delim := []byte(sep)
+	split := func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.Index(data, delim); i >= 0 {
+			return i + len(delim), data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil // request more data
+	}
+	return SplitFunc(r, split, opts...)
+}
+
+// Runes returns an iterator over the UTF-8-encoded runes read from r.
+// Each invalid byte is yielded as [unicode/utf8.RuneError].
+// If reading from r fails, the iterator yields
+// the zero value and the resulting error as its last pair.
+func Runes(r io.Reader) iter.Seq2[rune, error] {
+	return func(yield func(rune, error) bool) {
+		br := bufio.NewReader(r)
+		for {
+			c, _, err := br.ReadRune()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			if !yield(c, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Bytes returns an iterator over the bytes read from r.
+// If reading from r fails, the iterator yields
+// the zero value and the resulting error as its last pair.
+func Bytes(r io.Reader) iter.Seq2[byte, error] {
+	return func(yield func(byte, error) bool) {
+		br := bufio.NewReader(r)
+		for {
+			b, err := br.ReadByte()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			if !yield(b, nil) {
+				return
+			}
+		}
+	}
+}
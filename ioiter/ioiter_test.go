@@ -0,0 +1,338 @@
+package ioiter_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jub0bs/iterutil/ioiter"
+	"github.com/jub0bs/iterutil/iterutiltest"
+)
+
+var errBoom = errors.New("boom")
+
+// failingReader returns a reader that yields the contents of s
+// and then fails with errBoom.
+func failingReader(s string) io.Reader {
+	return io.MultiReader(strings.NewReader(s), iotest.ErrReader(errBoom))
+}
+
+func ExampleLines() {
+	r := strings.NewReader("foo\nbar\r\nbaz")
+	for line, err := range ioiter.Lines(r) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(line)
+	}
+	// Output:
+	// foo
+	// bar
+	// baz
+}
+
+func TestLines(t *testing.T) {
+	cases := []struct {
+		desc string
+		r    io.Reader
+		opts []ioiter.Option
+		want []iterutiltest.Pair[string, error]
+	}{
+		{
+			desc: "empty",
+			r:    strings.NewReader(""),
+		}, {
+			desc: "trailing newline",
+			r:    strings.NewReader("foo\n\nbar\n"),
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: ""},
+				{Key: "bar"},
+			},
+		}, {
+			desc: "one byte at a time",
+			r:    iotest.OneByteReader(strings.NewReader("foo\nbar")),
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: "bar"},
+			},
+		}, {
+			desc: "read error",
+			r:    failingReader("foo\nbar"),
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: "bar"},
+				{Key: "", Value: errBoom},
+			},
+		}, {
+			desc: "token too long",
+			r:    strings.NewReader("foo\nquux\nbar"),
+			opts: []ioiter.Option{ioiter.MaxTokenSize(4)},
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: "", Value: bufio.ErrTooLong},
+			},
+		}, {
+			desc: "token exactly as long as the maximum",
+			r:    strings.NewReader("foo\nquux\n"),
+			opts: []ioiter.Option{ioiter.MaxTokenSize(5)},
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: "quux"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := ioiter.Lines(tc.r, tc.opts...)
+			iterutiltest.AssertSeq2(t, got, tc.want)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestMaxTokenSizePanics(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		f := func(t *testing.T) {
+			defer func() {
+				const want = "max token size must be positive"
+				if r := recover(); r != want {
+					t.Errorf("got panic %v; want %v", r, want)
+				}
+			}()
+			ioiter.MaxTokenSize(n)
+		}
+		t.Run(fmt.Sprint(n), f)
+	}
+}
+
+func ExampleSplitFunc() {
+	r := strings.NewReader("The quick  brown\tfox")
+	for word, err := range ioiter.SplitFunc(r, bufio.ScanWords) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(word)
+	}
+	// Output:
+	// The
+	// quick
+	// brown
+	// fox
+}
+
+func TestSplitFuncEarlyBreak(t *testing.T) {
+	want := []string{"foo", "bar", "baz"}
+	for i := range len(want) + 1 {
+		r := strings.NewReader("foo bar baz")
+		var got []string
+		for word, err := range ioiter.SplitFunc(r, bufio.ScanWords) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) == i {
+				break
+			}
+			got = append(got, word)
+		}
+		if !slices.Equal(got, want[:i]) {
+			t.Errorf("got %q; want %q", got, want[:i])
+		}
+	}
+}
+
+func ExampleDelimited() {
+	r := strings.NewReader("foo, bar, baz, ")
+	for record, err := range ioiter.Delimited(r, ", ") {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(record)
+	}
+	// Output:
+	// foo
+	// bar
+	// baz
+}
+
+func TestDelimited(t *testing.T) {
+	cases := []struct {
+		desc      string
+		r         io.Reader
+		sep       string
+		opts      []ioiter.Option
+		want      []iterutiltest.Pair[string, error]
+		wantPanic bool
+	}{
+		{
+			desc:      "empty separator",
+			r:         strings.NewReader("foo"),
+			wantPanic: true,
+		}, {
+			desc: "empty",
+			r:    strings.NewReader(""),
+			sep:  "--",
+		}, {
+			desc: "no separator",
+			r:    strings.NewReader("foo"),
+			sep:  "--",
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+			},
+		}, {
+			desc: "empty records",
+			r:    strings.NewReader("----foo--"),
+			sep:  "--",
+			want: []iterutiltest.Pair[string, error]{
+				{Key: ""},
+				{Key: ""},
+				{Key: "foo"},
+			},
+		}, {
+			desc: "separator split across reads",
+			r:    iotest.OneByteReader(strings.NewReader("foo--bar")),
+			sep:  "--",
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: "bar"},
+			},
+		}, {
+			desc: "read error",
+			r:    failingReader("foo--bar"),
+			sep:  "--",
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: "bar"},
+				{Key: "", Value: errBoom},
+			},
+		}, {
+			desc: "record too long",
+			r:    strings.NewReader("foo--quux--bar"),
+			sep:  "--",
+			opts: []ioiter.Option{ioiter.MaxTokenSize(5)},
+			want: []iterutiltest.Pair[string, error]{
+				{Key: "foo"},
+				{Key: "", Value: bufio.ErrTooLong},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			if tc.wantPanic {
+				defer func() {
+					if recover() == nil {
+						t.Fatalf("got no panic; want panic")
+					}
+				}()
+			}
+			got := ioiter.Delimited(tc.r, tc.sep, tc.opts...)
+			iterutiltest.AssertSeq2(t, got, tc.want)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleRunes() {
+	r := strings.NewReader("héllo")
+	for c, err := range ioiter.Runes(r) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("%c ", c)
+	}
+	// Output:
+	// h é l l o
+}
+
+func TestRunes(t *testing.T) {
+	cases := []struct {
+		desc string
+		r    io.Reader
+		want []iterutiltest.Pair[rune, error]
+	}{
+		{
+			desc: "empty",
+			r:    strings.NewReader(""),
+		}, {
+			desc: "invalid UTF-8",
+			r:    strings.NewReader("a\xffé"),
+			want: []iterutiltest.Pair[rune, error]{
+				{Key: 'a'},
+				{Key: '�'},
+				{Key: 'é'},
+			},
+		}, {
+			desc: "read error",
+			r:    failingReader("aé"),
+			want: []iterutiltest.Pair[rune, error]{
+				{Key: 'a'},
+				{Key: 'é'},
+				{Key: 0, Value: errBoom},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := ioiter.Runes(tc.r)
+			iterutiltest.AssertSeq2(t, got, tc.want)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleBytes() {
+	r := strings.NewReader("hé")
+	for b, err := range ioiter.Bytes(r) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("%#x ", b)
+	}
+	// Output:
+	// 0x68 0xc3 0xa9
+}
+
+func TestBytes(t *testing.T) {
+	cases := []struct {
+		desc string
+		r    io.Reader
+		want []iterutiltest.Pair[byte, error]
+	}{
+		{
+			desc: "empty",
+			r:    strings.NewReader(""),
+		}, {
+			desc: "no error",
+			r:    strings.NewReader("ab"),
+			want: []iterutiltest.Pair[byte, error]{
+				{Key: 'a'},
+				{Key: 'b'},
+			},
+		}, {
+			desc: "read error",
+			r:    failingReader("ab"),
+			want: []iterutiltest.Pair[byte, error]{
+				{Key: 'a'},
+				{Key: 'b'},
+				{Key: 0, Value: errBoom},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := ioiter.Bytes(tc.r)
+			iterutiltest.AssertSeq2(t, got, tc.want)
+		}
+		t.Run(tc.desc, f)
+	}
+}
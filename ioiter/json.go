@@ -0,0 +1,127 @@
+package ioiter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// DecodeJSONArray returns an iterator over the elements of the JSON array
+// read from r, each of which is decoded into a value of type T
+// as per [json.Unmarshal].
+// The elements are decoded one at a time, without the whole array ever being
+// held in memory.
+// If reading from r or decoding fails, the iterator yields
+// the zero value and the resulting error as its last pair.
+func DecodeJSONArray[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		dec := json.NewDecoder(r)
+		if err := expectDelim(dec, '['); err != nil {
+			yield(zero, err)
+			return
+		}
+		for dec.More() {
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("ioiter: unexpected JSON token %v; want %v", tok, want)
+	}
+	return nil
+}
+
+// DecodeNDJSON returns an iterator over the [newline-delimited JSON] values
+// read from r, each of which is decoded into a value of type T
+// as per [json.Unmarshal].
+// If reading from r or decoding fails, the iterator yields
+// the zero value and the resulting error as its last pair.
+//
+// [newline-delimited JSON]: https://github.com/ndjson/ndjson-spec
+func DecodeNDJSON[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		dec := json.NewDecoder(r)
+		for {
+			var v T
+			err := dec.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// EncodeJSONArray writes to w a JSON array composed of
+// the elements of seq, each of which is encoded as per [json.Marshal].
+// The elements are encoded and written one at a time,
+// without the whole array ever being held in memory.
+// EncodeJSONArray stops at and returns the first error encountered.
+// It terminates if and only if seq is finite.
+func EncodeJSONArray[E any](w io.Writer, seq iter.Seq[E]) error {
+	sep := []byte{'['}
+	for e := range seq {
+		if _, err := w.Write(sep); err != nil {
+			return err
+		}
+		sep[0] = ','
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	if sep[0] == '[' { // seq is empty
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// EncodeNDJSON writes to w the elements of seq as [newline-delimited JSON],
+// each of them being encoded as per [json.Marshal].
+// EncodeNDJSON stops at and returns the first error encountered.
+// It terminates if and only if seq is finite.
+//
+// [newline-delimited JSON]: https://github.com/ndjson/ndjson-spec
+func EncodeNDJSON[E any](w io.Writer, seq iter.Seq[E]) error {
+	enc := json.NewEncoder(w)
+	for e := range seq {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package ioiter_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/ioiter"
+	"github.com/jub0bs/iterutil/iterutiltest"
+)
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func ExampleDecodeJSONArray() {
+	r := strings.NewReader(`[
+		{"name": "alice", "age": 42},
+		{"name": "bob", "age": 17},
+		{"name": "carol", "age": 35}
+	]`)
+	for u, err := range ioiter.DecodeJSONArray[user](r) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(u.Name, u.Age)
+	}
+	// Output:
+	// alice 42
+	// bob 17
+	// carol 35
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	cases := []struct {
+		desc    string
+		r       io.Reader
+		want    []iterutiltest.Pair[int, error]
+		wantErr bool // whether the last pair contains a non-nil error
+	}{
+		{
+			desc: "empty array",
+			r:    strings.NewReader(" [ ] "),
+		}, {
+			desc: "no error",
+			r:    strings.NewReader("[1, 2, 3]"),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+				{Key: 2},
+				{Key: 3},
+			},
+		}, {
+			desc: "one byte at a time",
+			r:    iotest.OneByteReader(strings.NewReader("[1, 2]")),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+				{Key: 2},
+			},
+		}, {
+			desc:    "empty input",
+			r:       strings.NewReader(""),
+			wantErr: true,
+		}, {
+			desc:    "not an array",
+			r:       strings.NewReader(`{"foo": 1}`),
+			wantErr: true,
+		}, {
+			desc: "unterminated array",
+			r:    strings.NewReader("[1, 2"),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+				{Key: 2},
+			},
+			wantErr: true,
+		}, {
+			desc: "element of wrong type",
+			r:    strings.NewReader(`[1, "two", 3]`),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+			},
+			wantErr: true,
+		}, {
+			desc: "read error",
+			r:    failingReader("[1, 2,"),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+				{Key: 2},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := ioiter.DecodeJSONArray[int](tc.r)
+			assertFallible(t, got, tc.want, tc.wantErr)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleDecodeNDJSON() {
+	r := strings.NewReader(`{"name": "alice", "age": 42}
+{"name": "bob", "age": 17}
+{"name": "carol", "age": 35}
+`)
+	isAdult := func(u user) bool { return u.Age >= 18 }
+	for u, err := range ioiter.DecodeNDJSON[user](r) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if isAdult(u) {
+			fmt.Println(u.Name)
+		}
+	}
+	// Output:
+	// alice
+	// carol
+}
+
+func TestDecodeNDJSON(t *testing.T) {
+	cases := []struct {
+		desc    string
+		r       io.Reader
+		want    []iterutiltest.Pair[int, error]
+		wantErr bool // whether the last pair contains a non-nil error
+	}{
+		{
+			desc: "empty",
+			r:    strings.NewReader(""),
+		}, {
+			desc: "no error",
+			r:    strings.NewReader("1\n2\n3\n"),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+				{Key: 2},
+				{Key: 3},
+			},
+		}, {
+			desc: "no trailing newline",
+			r:    strings.NewReader("1\n2"),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+				{Key: 2},
+			},
+		}, {
+			desc: "syntax error",
+			r:    strings.NewReader("1\n}\n3\n"),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+			},
+			wantErr: true,
+		}, {
+			desc: "read error",
+			r:    failingReader("1\n2\n"),
+			want: []iterutiltest.Pair[int, error]{
+				{Key: 1},
+				{Key: 2},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := ioiter.DecodeNDJSON[int](tc.r)
+			assertFallible(t, got, tc.want, tc.wantErr)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleEncodeJSONArray() {
+	seq := slices.Values([]user{{"alice", 42}, {"bob", 17}})
+	var sb strings.Builder
+	if err := ioiter.EncodeJSONArray(&sb, seq); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(sb.String())
+	// Output:
+	// [{"name":"alice","age":42},{"name":"bob","age":17}]
+}
+
+func TestEncodeJSONArray(t *testing.T) {
+	cases := []struct {
+		desc    string
+		elems   []any
+		w       *limitedWriter
+		want    string
+		wantErr bool
+	}{
+		{
+			desc: "empty",
+			w:    &limitedWriter{n: 100},
+			want: "[]",
+		}, {
+			desc:  "no error",
+			elems: []any{1, "two", nil},
+			w:     &limitedWriter{n: 100},
+			want:  `[1,"two",null]`,
+		}, {
+			desc:    "encoding error",
+			elems:   []any{1, func() {}},
+			w:       &limitedWriter{n: 100},
+			want:    "[1,",
+			wantErr: true,
+		}, {
+			desc:    "write error",
+			elems:   []any{1, 2, 3},
+			w:       &limitedWriter{n: 3},
+			want:    "[1,",
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			err := ioiter.EncodeJSONArray(tc.w, slices.Values(tc.elems))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error %v; want error: %t", err, tc.wantErr)
+			}
+			if got := tc.w.sb.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleEncodeNDJSON() {
+	// round trip
+	r := strings.NewReader(`[{"name":"alice","age":42},{"name":"bob","age":17}]`)
+	users := iterutil.Left(ioiter.DecodeJSONArray[user](r)) // errors ignored for brevity
+	var sb strings.Builder
+	if err := ioiter.EncodeNDJSON(&sb, users); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(sb.String())
+	// Output:
+	// {"name":"alice","age":42}
+	// {"name":"bob","age":17}
+}
+
+func TestEncodeNDJSON(t *testing.T) {
+	cases := []struct {
+		desc    string
+		elems   []any
+		w       *limitedWriter
+		want    string
+		wantErr bool
+	}{
+		{
+			desc: "empty",
+			w:    &limitedWriter{n: 100},
+		}, {
+			desc:  "no error",
+			elems: []any{1, "two", nil},
+			w:     &limitedWriter{n: 100},
+			want:  "1\n\"two\"\nnull\n",
+		}, {
+			desc:    "encoding error",
+			elems:   []any{1, func() {}},
+			w:       &limitedWriter{n: 100},
+			want:    "1\n",
+			wantErr: true,
+		}, {
+			desc:    "write error",
+			elems:   []any{1, 2, 3},
+			w:       &limitedWriter{n: 3},
+			want:    "1\n",
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			err := ioiter.EncodeNDJSON(tc.w, slices.Values(tc.elems))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error %v; want error: %t", err, tc.wantErr)
+			}
+			if got := tc.w.sb.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+// limitedWriter accepts only whole writes that fit in its remaining
+// capacity n, and fails with errBoom otherwise.
+type limitedWriter struct {
+	sb strings.Builder
+	n  int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return 0, errBoom
+	}
+	w.n -= len(p)
+	return w.sb.Write(p)
+}
+
+// assertFallible checks that seq yields the pairs in want
+// followed, if wantErr is true, by a pair whose error is non-nil.
+func assertFallible[E comparable](
+	t *testing.T,
+	seq iter.Seq2[E, error],
+	want []iterutiltest.Pair[E, error],
+	wantErr bool,
+) {
+	t.Helper()
+	var got []iterutiltest.Pair[E, error]
+	var gotErr error
+	for e, err := range seq {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, iterutiltest.Pair[E, error]{Key: e})
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if (gotErr != nil) != wantErr {
+		t.Errorf("got error %v; want error: %t", gotErr, wantErr)
+	}
+	if gotErr != nil && wantErr && errors.Is(gotErr, io.EOF) {
+		t.Errorf("got io.EOF; want a more specific error")
+	}
+}
@@ -0,0 +1,180 @@
+// Package iterutiltest provides utilities for testing iterators.
+package iterutiltest
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/jub0bs/iterutil"
+)
+
+// A Pair is a key-value pair, as yielded by an [iter.Seq2].
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// String returns a string representation of p.
+func (p Pair[K, V]) String() string {
+	return fmt.Sprintf("(%v,%v)", p.Key, p.Value)
+}
+
+// AssertSeq reports an error to t, along with a diff,
+// unless seq yields exactly the elements of want, in order.
+// Because AssertSeq stops consuming seq after len(want)+1 elements,
+// it terminates even if seq is infinite.
+func AssertSeq[E comparable](t testing.TB, seq iter.Seq[E], want []E) {
+	t.Helper()
+	got, r := collect(seq, len(want)+1)
+	if r != nil {
+		t.Errorf("misbehaving iterator: %v", r)
+		return
+	}
+	if d := diff(got, want); d != "" {
+		t.Errorf("unexpected elements (-got +want):\n%s", d)
+	}
+}
+
+// AssertSeq2 reports an error to t, along with a diff,
+// unless seq yields exactly the pairs of want, in order.
+// Because AssertSeq2 stops consuming seq after len(want)+1 pairs,
+// it terminates even if seq is infinite.
+func AssertSeq2[K, V comparable](t testing.TB, seq iter.Seq2[K, V], want []Pair[K, V]) {
+	t.Helper()
+	got, r := collect(pairs(seq), len(want)+1)
+	if r != nil {
+		t.Errorf("misbehaving iterator: %v", r)
+		return
+	}
+	if d := diff(got, want); d != "" {
+		t.Errorf("unexpected pairs (-got +want):\n%s", d)
+	}
+}
+
+// CheckEarlyBreakAtEveryIndex reports an error to t
+// unless, for every i in [0, len(want)],
+// a loop over seq that breaks after i elements
+// observes exactly the first i elements of want
+// and seq stops calling yield as soon as yield returns false.
+// Because it ranges over seq several times,
+// CheckEarlyBreakAtEveryIndex is only meaningful if seq is re-iterable.
+func CheckEarlyBreakAtEveryIndex[E comparable](t testing.TB, seq iter.Seq[E], want []E) {
+	t.Helper()
+	for i := range len(want) + 1 {
+		got, r := collect(seq, i)
+		if r != nil {
+			t.Errorf("misbehaving iterator when breaking after %d elements: %v", i, r)
+			continue
+		}
+		if d := diff(got, want[:i]); d != "" {
+			const tmpl = "unexpected elements when breaking after %d elements (-got +want):\n%s"
+			t.Errorf(tmpl, i, d)
+		}
+	}
+}
+
+// CheckReiterable reports an error to t
+// unless ranging over seq twice yields the same elements both times.
+// It terminates if and only if seq is finite;
+// to check an infinite iterator, limit it with [iterutil.Take].
+func CheckReiterable[E comparable](t testing.TB, seq iter.Seq[E]) {
+	t.Helper()
+	first, r := collect(seq, -1)
+	if r != nil {
+		t.Errorf("misbehaving iterator on first iteration: %v", r)
+		return
+	}
+	second, r := collect(seq, -1)
+	if r != nil {
+		t.Errorf("misbehaving iterator on second iteration: %v", r)
+		return
+	}
+	if d := diff(second, first); d != "" {
+		const tmpl = "second iteration differs from first (-second +first):\n%s"
+		t.Errorf(tmpl, d)
+	}
+}
+
+// CheckNoYieldAfterFalse reports an error to t
+// if seq calls yield after yield has returned false,
+// regardless of the index at which yield returns false.
+// Because it ranges over seq several times,
+// CheckNoYieldAfterFalse is only meaningful if seq is re-iterable.
+// It terminates if and only if seq is finite;
+// to check an infinite iterator, limit it with [iterutil.Take].
+func CheckNoYieldAfterFalse[E any](t testing.TB, seq iter.Seq[E]) {
+	t.Helper()
+	for i := 0; ; i++ {
+		got, r := collect(seq, i)
+		if r != nil {
+			t.Errorf("misbehaving iterator when breaking after %d elements: %v", i, r)
+		}
+		if len(got) < i { // seq is exhausted
+			return
+		}
+	}
+}
+
+// collect, if n is non-negative, collects up to n elements of seq;
+// otherwise, it collects all the elements of seq.
+// If seq misuses its yield function, collect recovers from the resulting
+// panic and returns its value.
+func collect[E any](seq iter.Seq[E], n int) (es []E, r any) {
+	defer func() {
+		r = recover()
+	}()
+	if n == 0 {
+		// Even when the caller is not interested in any element,
+		// seq should stop as soon as yield returns false.
+		iterutil.Checked(seq)(func(E) bool { return false })
+		return
+	}
+	for e := range iterutil.Checked(seq) {
+		es = append(es, e)
+		if len(es) == n {
+			break
+		}
+	}
+	return
+}
+
+func pairs[K, V any](seq iter.Seq2[K, V]) iter.Seq[Pair[K, V]] {
+	return func(yield func(Pair[K, V]) bool) {
+		for k, v := range seq {
+			if !yield(Pair[K, V]{k, v}) {
+				return
+			}
+		}
+	}
+}
+
+// diff returns a line-oriented, index-annotated diff of got and want,
+// or the empty string if got and want are equal.
+func diff[E comparable](got, want []E) string {
+	var (
+		sb      strings.Builder
+		differs bool
+	)
+	for i := range max(len(got), len(want)) {
+		switch {
+		case i >= len(got):
+			differs = true
+			fmt.Fprintf(&sb, "+ %d: %v\n", i, want[i])
+		case i >= len(want):
+			differs = true
+			fmt.Fprintf(&sb, "- %d: %v\n", i, got[i])
+		case got[i] != want[i]:
+			differs = true
+			fmt.Fprintf(&sb, "- %d: %v\n", i, got[i])
+			fmt.Fprintf(&sb, "+ %d: %v\n", i, want[i])
+		default:
+			fmt.Fprintf(&sb, "  %d: %v\n", i, got[i])
+		}
+	}
+	if !differs {
+		return ""
+	}
+	return sb.String()
+}
@@ -0,0 +1,229 @@
+package iterutiltest_test
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/iterutiltest"
+)
+
+func ExampleAssertSeq() {
+	var t fakeT
+	seq := iterutil.Map(slices.Values([]string{"one", "two", "three"}), strings.ToUpper)
+	iterutiltest.AssertSeq(&t, seq, []string{"ONE", "TWO", "FOUR"})
+	fmt.Print(t.errors[0])
+	// Output:
+	// unexpected elements (-got +want):
+	//   0: ONE
+	//   1: TWO
+	// - 2: THREE
+	// + 2: FOUR
+}
+
+func TestAssertSeq(t *testing.T) {
+	cases := []struct {
+		desc     string
+		seq      iter.Seq[int]
+		want     []int
+		wantErrs int
+	}{
+		{
+			desc: "equal",
+			seq:  slices.Values([]int{1, 2, 3}),
+			want: []int{1, 2, 3},
+		}, {
+			desc: "empty",
+			seq:  iterutil.Empty[int](),
+		}, {
+			desc:     "too many elements",
+			seq:      slices.Values([]int{1, 2, 3}),
+			want:     []int{1, 2},
+			wantErrs: 1,
+		}, {
+			desc:     "not enough elements",
+			seq:      slices.Values([]int{1, 2}),
+			want:     []int{1, 2, 3},
+			wantErrs: 1,
+		}, {
+			desc:     "infinite",
+			seq:      iterutil.Repeat(1, -1),
+			want:     []int{1, 1, 1},
+			wantErrs: 1,
+		}, {
+			desc:     "misbehaving",
+			seq:      ignoresFalse,
+			want:     []int{0},
+			wantErrs: 1,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var ft fakeT
+			iterutiltest.AssertSeq(&ft, tc.seq, tc.want)
+			if len(ft.errors) != tc.wantErrs {
+				t.Errorf("got %d errors %q; want %d", len(ft.errors), ft.errors, tc.wantErrs)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestAssertSeq2(t *testing.T) {
+	type Pair = iterutiltest.Pair[string, int]
+	cases := []struct {
+		desc     string
+		m        map[string]int
+		want     []Pair
+		wantErrs int
+	}{
+		{
+			desc: "equal",
+			m:    map[string]int{"one": 1, "two": 2},
+			want: []Pair{{"one", 1}, {"two", 2}},
+		}, {
+			desc:     "different",
+			m:        map[string]int{"one": 1, "two": 2},
+			want:     []Pair{{"one", 1}, {"two", 3}},
+			wantErrs: 1,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var ft fakeT
+			seq := iterutil.SortedFromMap(tc.m)
+			iterutiltest.AssertSeq2(&ft, seq, tc.want)
+			if len(ft.errors) != tc.wantErrs {
+				t.Errorf("got %d errors %q; want %d", len(ft.errors), ft.errors, tc.wantErrs)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestCheckEarlyBreakAtEveryIndex(t *testing.T) {
+	cases := []struct {
+		desc     string
+		seq      iter.Seq[int]
+		want     []int
+		wantErrs int
+	}{
+		{
+			desc: "well behaved",
+			seq:  slices.Values([]int{0, 1, 2}),
+			want: []int{0, 1, 2},
+		}, {
+			desc:     "misbehaving",
+			seq:      ignoresFalse,
+			want:     []int{0, 1},
+			wantErrs: 2, // when breaking after 0 and 1 elements
+		}, {
+			desc:     "single use",
+			seq:      singleUse(1, 2),
+			want:     []int{1, 2},
+			wantErrs: 2, // when breaking after 1 and 2 elements
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var ft fakeT
+			iterutiltest.CheckEarlyBreakAtEveryIndex(&ft, tc.seq, tc.want)
+			if len(ft.errors) != tc.wantErrs {
+				t.Errorf("got %d errors %q; want %d", len(ft.errors), ft.errors, tc.wantErrs)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestCheckReiterable(t *testing.T) {
+	cases := []struct {
+		desc     string
+		seq      iter.Seq[int]
+		wantErrs int
+	}{
+		{
+			desc: "re-iterable",
+			seq:  slices.Values([]int{1, 2, 3}),
+		}, {
+			desc: "re-iterable but unordered",
+			seq:  maps.Keys(map[int]struct{}{1: {}}),
+		}, {
+			desc:     "single use",
+			seq:      singleUse(1, 2, 3),
+			wantErrs: 1,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var ft fakeT
+			iterutiltest.CheckReiterable(&ft, tc.seq)
+			if len(ft.errors) != tc.wantErrs {
+				t.Errorf("got %d errors %q; want %d", len(ft.errors), ft.errors, tc.wantErrs)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestCheckNoYieldAfterFalse(t *testing.T) {
+	cases := []struct {
+		desc     string
+		seq      iter.Seq[int]
+		wantErrs int
+	}{
+		{
+			desc: "well behaved",
+			seq:  slices.Values([]int{1, 2, 3}),
+		}, {
+			desc:     "misbehaving",
+			seq:      ignoresFalse,
+			wantErrs: 2, // when breaking after 0 and 1 elements
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var ft fakeT
+			iterutiltest.CheckNoYieldAfterFalse(&ft, tc.seq)
+			if len(ft.errors) != tc.wantErrs {
+				t.Errorf("got %d errors %q; want %d", len(ft.errors), ft.errors, tc.wantErrs)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+// ignoresFalse yields 0 and 1, regardless of what yield returns.
+func ignoresFalse(yield func(int) bool) {
+	yield(0)
+	yield(1)
+}
+
+// singleUse returns an iterator over elems that can only be consumed once.
+func singleUse(elems ...int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for len(elems) > 0 {
+			e := elems[0]
+			elems = elems[1:]
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// fakeT records the errors reported to it.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (*fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,196 @@
+package iterutil_test
+
+// This file contains fuzz targets that check algebraic laws
+// which the package's combinators and sources are expected to obey.
+// Each target also checks that the iterators under test are re-iterable,
+// which guards against stateful closures.
+
+import (
+	"cmp"
+	"encoding/binary"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/iterutiltest"
+)
+
+// Map(Map(seq, f), g) == Map(seq, g∘f)
+func FuzzMapFusion(f *testing.F) {
+	f.Add([]byte(nil))
+	f.Add([]byte("foo"))
+	f.Add([]byte("Hello, 世界"))
+	f.Fuzz(func(t *testing.T, elems []byte) {
+		double := func(b byte) int { return 2 * int(b) }
+		itoa := strconv.Itoa
+		seq := slices.Values(elems)
+		got := iterutil.Map(iterutil.Map(seq, double), itoa)
+		want := iterutil.Map(seq, func(b byte) string { return itoa(double(b)) })
+		iterutiltest.AssertSeq(t, got, slices.Collect(want))
+		iterutiltest.CheckReiterable(t, got)
+	})
+}
+
+// Concat(Take(seq, n), Drop(seq, n)) == seq
+func FuzzTakeDrop(f *testing.F) {
+	f.Add([]byte(nil), 0)
+	f.Add([]byte("foo"), -1)
+	f.Add([]byte("foo"), 0)
+	f.Add([]byte("foo"), 2)
+	f.Add([]byte("foo"), 3)
+	f.Add([]byte("foo"), 42)
+	f.Fuzz(func(t *testing.T, elems []byte, n int) {
+		seq := slices.Values(elems)
+		take := iterutil.Take(seq, n)
+		drop := iterutil.Drop(seq, n)
+		iterutiltest.AssertSeq(t, iterutil.Concat(take, drop), elems)
+		if got, want := iterutil.Len(take), min(max(n, 0), len(elems)); got != want {
+			t.Errorf("Len(Take(seq, %d)): got %d; want %d", n, got, want)
+		}
+		iterutiltest.CheckReiterable(t, take)
+		iterutiltest.CheckReiterable(t, drop)
+	})
+}
+
+// Concat(TakeWhile(seq, p), DropWhile(seq, p)) == seq
+func FuzzTakeWhileDropWhile(f *testing.F) {
+	f.Add([]byte(nil), byte(0))
+	f.Add([]byte("foo"), byte('f'))
+	f.Add([]byte("foo"), byte('o'))
+	f.Add([]byte("foobar"), byte('p'))
+	f.Fuzz(func(t *testing.T, elems []byte, pivot byte) {
+		seq := slices.Values(elems)
+		p := func(b byte) bool { return b < pivot }
+		takeWhile := iterutil.TakeWhile(seq, p)
+		dropWhile := iterutil.DropWhile(seq, p)
+		iterutiltest.AssertSeq(t, iterutil.Concat(takeWhile, dropWhile), elems)
+		iterutiltest.CheckReiterable(t, takeWhile)
+		iterutiltest.CheckReiterable(t, dropWhile)
+	})
+}
+
+// Len(Filter(seq, p)) + Len(Filter(seq, not(p))) == Len(seq)
+func FuzzFilterPartition(f *testing.F) {
+	f.Add([]byte(nil))
+	f.Add([]byte("foo"))
+	f.Add([]byte("Hello, 世界"))
+	f.Fuzz(func(t *testing.T, elems []byte) {
+		seq := slices.Values(elems)
+		isEven := func(b byte) bool { return b%2 == 0 }
+		isOdd := func(b byte) bool { return b%2 != 0 }
+		evens := iterutil.Filter(seq, isEven)
+		odds := iterutil.Filter(seq, isOdd)
+		if got, want := iterutil.Len(evens)+iterutil.Len(odds), len(elems); got != want {
+			t.Errorf("got %d elements in both partitions; want %d", got, want)
+		}
+		iterutiltest.CheckReiterable(t, evens)
+	})
+}
+
+// Len(Concat(seq1, seq2)) == Len(seq1) + Len(seq2)
+// Flatten(SeqOf(seq1, seq2)) == Concat(seq1, seq2)
+func FuzzConcat(f *testing.F) {
+	f.Add([]byte(nil), []byte(nil))
+	f.Add([]byte("foo"), []byte(nil))
+	f.Add([]byte(nil), []byte("bar"))
+	f.Add([]byte("foo"), []byte("bar"))
+	f.Fuzz(func(t *testing.T, elems1, elems2 []byte) {
+		seq1 := slices.Values(elems1)
+		seq2 := slices.Values(elems2)
+		concat := iterutil.Concat(seq1, seq2)
+		got := iterutil.Len(concat)
+		want := iterutil.Len(seq1) + iterutil.Len(seq2)
+		if got != want {
+			t.Errorf("Len(Concat(seq1, seq2)): got %d; want %d", got, want)
+		}
+		flat := iterutil.Flatten(iterutil.SeqOf(seq1, seq2))
+		iterutiltest.AssertSeq(t, flat, slices.Collect(concat))
+		iterutiltest.CheckReiterable(t, concat)
+		iterutiltest.CheckReiterable(t, flat)
+	})
+}
+
+// Left(Zip(seq1, seq2)) == Take(seq1, min(Len(seq1), Len(seq2)))
+// Right(Zip(seq1, seq2)) == Take(seq2, min(Len(seq1), Len(seq2)))
+func FuzzZip(f *testing.F) {
+	f.Add([]byte(nil), []byte(nil))
+	f.Add([]byte("foo"), []byte(nil))
+	f.Add([]byte("foo"), []byte("quux"))
+	f.Fuzz(func(t *testing.T, elems1, elems2 []byte) {
+		seq1 := slices.Values(elems1)
+		seq2 := slices.Values(elems2)
+		zip := iterutil.Zip(seq1, seq2)
+		n := min(len(elems1), len(elems2))
+		iterutiltest.AssertSeq(t, iterutil.Left(zip), elems1[:n])
+		iterutiltest.AssertSeq(t, iterutil.Right(zip), elems2[:n])
+		pair := func(a, b byte) [2]byte { return [2]byte{a, b} }
+		zipWith := iterutil.ZipWith(seq1, seq2, pair)
+		iterutiltest.CheckReiterable(t, zipWith)
+	})
+}
+
+// Swap(Swap(seq)) == seq
+// Right(Enumerate(seq)) == seq
+func FuzzSwapSwap(f *testing.F) {
+	f.Add([]byte(nil))
+	f.Add([]byte("foo"))
+	f.Add([]byte("Hello, 世界"))
+	f.Fuzz(func(t *testing.T, elems []byte) {
+		seq := slices.All(elems)
+		var want []iterutiltest.Pair[int, byte]
+		for i, b := range seq {
+			want = append(want, iterutiltest.Pair[int, byte]{Key: i, Value: b})
+		}
+		iterutiltest.AssertSeq2(t, iterutil.Swap(iterutil.Swap(seq)), want)
+		enumerate := iterutil.Enumerate[int](slices.Values(elems))
+		iterutiltest.AssertSeq2(t, enumerate, want)
+		iterutiltest.AssertSeq(t, iterutil.Right(enumerate), elems)
+	})
+}
+
+// SortedFromMap(m) agrees with slices.Sorted(maps.Keys(m)).
+func FuzzSortedFromMap(f *testing.F) {
+	f.Add([]byte(nil))
+	f.Add([]byte("foobar"))
+	// enough keys to exceed the threshold above which a heap is used
+	large := make([]byte, 0, 2048)
+	for i := range uint16(1024) {
+		large = binary.BigEndian.AppendUint16(large, i*7919)
+	}
+	f.Add(large)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := make(map[uint16]int)
+		for i := 0; i+1 < len(data); i += 2 {
+			m[binary.BigEndian.Uint16(data[i:])] = i
+		}
+		keys := slices.Sorted(maps.Keys(m))
+		want := make([]iterutiltest.Pair[uint16, int], len(keys))
+		for i, k := range keys {
+			want[i] = iterutiltest.Pair[uint16, int]{Key: k, Value: m[k]}
+		}
+		iterutiltest.AssertSeq2(t, iterutil.SortedFromMap(m), want)
+		iterutiltest.AssertSeq2(t, iterutil.SortedFromMapFunc(m, cmp.Compare), want)
+		iterutiltest.CheckReiterable(t, iterutil.Left(iterutil.SortedFromMap(m)))
+	})
+}
+
+// Concat(DropLast(seq, n), TakeLast(seq, n)) == seq
+// TakeLast(seq, n) == LastN(seq, n)
+func FuzzDropLastTakeLast(f *testing.F) {
+	f.Add([]byte(nil), 0)
+	f.Add([]byte("foo"), -1)
+	f.Add([]byte("foo"), 2)
+	f.Add([]byte("foobar"), 4)
+	f.Add([]byte("foo"), 42)
+	f.Fuzz(func(t *testing.T, elems []byte, n int) {
+		seq := slices.Values(elems)
+		dropLast := iterutil.DropLast(seq, n)
+		takeLast := iterutil.TakeLast(seq, n)
+		iterutiltest.AssertSeq(t, iterutil.Concat(dropLast, takeLast), elems)
+		iterutiltest.AssertSeq(t, takeLast, iterutil.LastN(seq, n))
+		iterutiltest.CheckReiterable(t, dropLast)
+		iterutiltest.CheckReiterable(t, takeLast)
+	})
+}
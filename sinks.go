@@ -2,8 +2,15 @@ package iterutil
 
 import (
 	"cmp"
+	"errors"
+	"io"
 	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
 
+	"github.com/jub0bs/iterutil/internal"
 	"golang.org/x/exp/constraints"
 )
 
@@ -81,6 +88,146 @@ func EqualFunc[A, B comparable](seq1 iter.Seq[A], seq2 iter.Seq[B], eq func(A, B
 	}
 }
 
+// HasPrefix reports whether seq begins with all the elements of prefix,
+// in order.
+// It terminates if seq or prefix (or both) is finite.
+func HasPrefix[E comparable](seq, prefix iter.Seq[E]) bool {
+	return HasPrefixFunc(seq, prefix, equal)
+}
+
+// HasPrefixFunc is like [HasPrefix] but uses eq to compare elements.
+func HasPrefixFunc[A, B any](seq iter.Seq[A], prefix iter.Seq[B], eq func(A, B) bool) bool {
+	next, stop := iter.Pull(prefix)
+	defer stop()
+	for a := range seq {
+		b, ok := next()
+		if !ok {
+			return true
+		}
+		if !eq(a, b) {
+			return false
+		}
+	}
+	_, ok := next()
+	return !ok
+}
+
+// HasSuffix reports whether seq ends with all the elements of suffix,
+// in order.
+// HasSuffix collects the elements of suffix but only retains
+// the last Len(suffix) elements of seq in a ring buffer.
+// It terminates if and only if both seq and suffix are finite.
+func HasSuffix[E comparable](seq, suffix iter.Seq[E]) bool {
+	return HasSuffixFunc(seq, suffix, equal)
+}
+
+// HasSuffixFunc is like [HasSuffix] but uses eq to compare elements.
+func HasSuffixFunc[A, B any](seq iter.Seq[A], suffix iter.Seq[B], eq func(A, B) bool) bool {
+	want := slices.Collect(suffix)
+	n := len(want)
+	if n == 0 {
+		return true
+	}
+	ring := make([]A, n)
+	var count int // number of elements of seq
+	for a := range seq {
+		ring[count%n] = a
+		count++
+	}
+	if count < n {
+		return false
+	}
+	// the oldest retained element is at index count%n
+	for i, b := range want {
+		if !eq(ring[(count+i)%n], b) {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexSeq returns the index of the first occurrence of the elements of
+// sub, in order and contiguously, in seq, or -1 if sub does not occur in seq.
+// If sub is empty, IndexSeq returns 0.
+// IndexSeq collects the elements of sub and relies on the
+// [Knuth-Morris-Pratt algorithm], which ranges over seq only once
+// and runs in time linear in the lengths of seq and sub.
+// IndexSeq may not terminate if seq is infinite;
+// it doesn't terminate if sub is infinite.
+//
+// [Knuth-Morris-Pratt algorithm]: https://en.wikipedia.org/wiki/Knuth%E2%80%93Morris%E2%80%93Pratt_algorithm
+func IndexSeq[E comparable](seq, sub iter.Seq[E]) int {
+	return IndexSeqFunc(seq, sub, equal)
+}
+
+// IndexSeqFunc is like [IndexSeq] but uses eq to compare elements;
+// eq must be an equivalence relation.
+func IndexSeqFunc[E any](seq, sub iter.Seq[E], eq func(E, E) bool) int {
+	m := newMatcher(slices.Collect(sub), eq)
+	if len(m.pattern) == 0 {
+		return 0
+	}
+	var i int
+	for e := range seq {
+		i++
+		if m.advance(e) {
+			return i - len(m.pattern)
+		}
+	}
+	return -1
+}
+
+// ContainsSubsequence reports whether the elements of sub occur in seq,
+// in order and contiguously; see [IndexSeq] for more details.
+func ContainsSubsequence[E comparable](seq, sub iter.Seq[E]) bool {
+	return IndexSeq(seq, sub) >= 0
+}
+
+// ContainsSubsequenceFunc is like [ContainsSubsequence]
+// but uses eq to compare elements; eq must be an equivalence relation.
+func ContainsSubsequenceFunc[E any](seq, sub iter.Seq[E], eq func(E, E) bool) bool {
+	return IndexSeqFunc(seq, sub, eq) >= 0
+}
+
+// A matcher incrementally searches for a non-empty pattern in a stream of
+// elements by means of the Knuth-Morris-Pratt algorithm.
+type matcher[E any] struct {
+	pattern []E
+	eq      func(E, E) bool
+	fail    []int // fail[i]: length of the longest proper border of pattern[:i+1]
+	matched int   // length of the current partial match
+}
+
+func newMatcher[E any](pattern []E, eq func(E, E) bool) *matcher[E] {
+	fail := make([]int, len(pattern))
+	for i, k := 1, 0; i < len(pattern); i++ {
+		for k > 0 && !eq(pattern[i], pattern[k]) {
+			k = fail[k-1]
+		}
+		if eq(pattern[i], pattern[k]) {
+			k++
+		}
+		fail[i] = k
+	}
+	return &matcher[E]{pattern: pattern, eq: eq, fail: fail}
+}
+
+// advance feeds e to m and reports whether it completes a match,
+// in which case m gets reset so that matches do not overlap.
+func (m *matcher[E]) advance(e E) bool {
+	for m.matched > 0 && !m.eq(e, m.pattern[m.matched]) {
+		m.matched = m.fail[m.matched-1]
+	}
+	if m.eq(e, m.pattern[m.matched]) {
+		m.matched++
+	}
+	if m.matched == len(m.pattern) {
+		m.matched = 0
+		return true
+	}
+	return false
+}
+
 // Contains report whether target is present in seq.
 // It may not terminate if seq is infinite.
 func Contains[E comparable](seq iter.Seq[E], target E) bool {
@@ -103,6 +250,235 @@ func ContainsFunc[E any](seq iter.Seq[E], p func(E) bool) bool {
 	return false
 }
 
+// All reports whether every element e of seq satisfies p(e);
+// in particular, All returns true if seq is empty.
+// It may not terminate if seq is infinite.
+func All[E any](seq iter.Seq[E], p func(E) bool) bool {
+	for e := range seq {
+		if !p(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// None reports whether no element e of seq satisfies p(e);
+// in particular, None returns true if seq is empty.
+// It may not terminate if seq is infinite.
+func None[E any](seq iter.Seq[E], p func(E) bool) bool {
+	return !ContainsFunc(seq, p)
+}
+
+// CountFunc returns the number of elements e of seq that satisfy p(e).
+// It terminates if and only if seq is finite.
+func CountFunc[E any](seq iter.Seq[E], p func(E) bool) int {
+	var n int
+	for e := range seq {
+		if p(e) {
+			n++
+		}
+	}
+	return n
+}
+
+// Errors returned by [ExactlyOne].
+var (
+	ErrNoElements       = errors.New("iterutil: no elements")
+	ErrMultipleElements = errors.New("iterutil: more than one element")
+)
+
+// ExactlyOne, if seq contains exactly one element, returns that element
+// and a nil error; otherwise, it returns the zero value and either
+// [ErrNoElements] or [ErrMultipleElements].
+// ExactlyOne stops ranging over seq as soon as it encounters
+// a second element.
+func ExactlyOne[E any](seq iter.Seq[E]) (E, error) {
+	var (
+		one E
+		n   int
+	)
+	for e := range seq {
+		if n > 0 {
+			var zero E
+			return zero, ErrMultipleElements
+		}
+		one = e
+		n++
+	}
+	if n == 0 {
+		return one, ErrNoElements
+	}
+	return one, nil
+}
+
+// AtMost reports whether seq contains at most n elements.
+// AtMost stops ranging over seq as soon as it encounters
+// the element of index n; in particular, it terminates if n is non-negative.
+func AtMost[I constraints.Integer, E any](seq iter.Seq[E], n I) bool {
+	if n < 0 {
+		return false
+	}
+	var count I
+	for range seq {
+		if count == n {
+			return false
+		}
+		count++
+	}
+	return true
+}
+
+// AtLeast reports whether seq contains at least n elements.
+// AtLeast stops ranging over seq as soon as it encounters
+// the element of index n-1; in particular, it terminates if seq contains
+// at least n elements.
+func AtLeast[I constraints.Integer, E any](seq iter.Seq[E], n I) bool {
+	if n <= 0 {
+		return true
+	}
+	var count I
+	for range seq {
+		count++
+		if count == n {
+			return true
+		}
+	}
+	return false
+}
+
+// First, if seq is not empty, returns the first element of seq and true;
+// otherwise, it returns the zero value and false.
+func First[E any](seq iter.Seq[E]) (E, bool) {
+	for e := range seq {
+		return e, true
+	}
+	var zero E
+	return zero, false
+}
+
+// Last, if seq is not empty, returns the last element of seq and true;
+// otherwise, it returns the zero value and false.
+// Last terminates if and only if seq is finite.
+func Last[E any](seq iter.Seq[E]) (E, bool) {
+	var (
+		last     E
+		nonEmpty bool
+	)
+	for e := range seq {
+		last = e
+		nonEmpty = true
+	}
+	return last, nonEmpty
+}
+
+// LastN returns a slice of the last min(max(n, 0), Len(seq)) elements of seq,
+// in order.
+// LastN retains at most n elements of seq at any given time.
+// It terminates if and only if seq is finite.
+func LastN[I constraints.Integer, E any](seq iter.Seq[E], n I) []E {
+	if n <= 0 {
+		return nil
+	}
+	var r ring[E]
+	for e := range seq {
+		if uint64(len(r.buf)) < uint64(n) {
+			r.buf = append(r.buf, e)
+			continue
+		}
+		r.push(e)
+	}
+	// Rotate the buffer so that the oldest element comes first.
+	slices.Reverse(r.buf[:r.head])
+	slices.Reverse(r.buf[r.head:])
+	slices.Reverse(r.buf)
+	return r.buf
+}
+
+// Find returns the first element e of seq that satisfies p(e) and true,
+// or the zero value and false if no such element exists.
+// It may not terminate if seq is infinite.
+func Find[E any](seq iter.Seq[E], p func(E) bool) (E, bool) {
+	for e := range seq {
+		if p(e) {
+			return e, true
+		}
+	}
+	var zero E
+	return zero, false
+}
+
+// Index returns the index of the first occurrence of target in seq,
+// or -1 if target is not present in seq.
+// It may not terminate if seq is infinite.
+func Index[E comparable](seq iter.Seq[E], target E) int {
+	return IndexFunc(seq, func(e E) bool { return e == target })
+}
+
+// IndexFunc returns the index of the first element e of seq
+// that satisfies p(e), or -1 if no such element exists.
+// It may not terminate if seq is infinite.
+func IndexFunc[E any](seq iter.Seq[E], p func(E) bool) int {
+	var i int
+	for e := range seq {
+		if p(e) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// LastIndex returns the index of the last occurrence of target in seq,
+// or -1 if target is not present in seq.
+// LastIndex terminates if and only if seq is finite.
+func LastIndex[E comparable](seq iter.Seq[E], target E) int {
+	return LastIndexFunc(seq, func(e E) bool { return e == target })
+}
+
+// LastIndexFunc returns the index of the last element e of seq
+// that satisfies p(e), or -1 if no such element exists.
+// LastIndexFunc terminates if and only if seq is finite.
+func LastIndexFunc[E any](seq iter.Seq[E], p func(E) bool) int {
+	var i int
+	last := -1
+	for e := range seq {
+		if p(e) {
+			last = i
+		}
+		i++
+	}
+	return last
+}
+
+// Find2 returns the first pair (k, v) of seq that satisfies p(k, v) and true,
+// or two zero values and false if no such pair exists.
+// It may not terminate if seq is infinite.
+func Find2[K, V any](seq iter.Seq2[K, V], p func(K, V) bool) (K, V, bool) {
+	for k, v := range seq {
+		if p(k, v) {
+			return k, v, true
+		}
+	}
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+// Lookup returns the value of the first pair of seq whose key is key and true,
+// or the zero value and false if no such pair exists.
+// It may not terminate if seq is infinite.
+func Lookup[K comparable, V any](seq iter.Seq2[K, V], key K) (V, bool) {
+	for k, v := range seq {
+		if k == key {
+			return v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
 // Min, if seq is not empty, returns the minimal value in seq and true;
 // otherwise, it returns the zero value and false.
 // For floating-point numbers, Min propagates NaNs
@@ -155,14 +531,18 @@ func MinFunc[E any](seq iter.Seq[E], cmp func(E, E) int) (E, bool) {
 // Max terminates if and only if seq is finite.
 func Max[E cmp.Ordered](seq iter.Seq[E]) (E, bool) {
 	var (
-		m        E
-		nonEmpty bool
+		m         E
+		firstSeen bool
 	)
 	for e := range seq {
-		nonEmpty = true
+		if !firstSeen {
+			m = e
+			firstSeen = true
+			continue
+		}
 		m = max(e, m)
 	}
-	return m, nonEmpty
+	return m, firstSeen
 }
 
 // MaxFunc, if seq is not empty, returns the maximal value
@@ -173,16 +553,168 @@ func Max[E cmp.Ordered](seq iter.Seq[E]) (E, bool) {
 // MaxFunc terminates if and only if seq is finite.
 func MaxFunc[E any](seq iter.Seq[E], cmp func(E, E) int) (E, bool) {
 	var (
-		m        E
-		nonEmpty bool
+		m         E
+		firstSeen bool
 	)
 	for e := range seq {
-		nonEmpty = true
+		if !firstSeen {
+			m = e
+			firstSeen = true
+			continue
+		}
 		if cmp(e, m) > 0 {
 			m = e
 		}
 	}
-	return m, nonEmpty
+	return m, firstSeen
+}
+
+// MinMax, if seq is not empty, returns the minimal and maximal values in seq
+// and true; otherwise, it returns two zero values and false.
+// Unlike calling both [Min] and [Max], MinMax ranges over seq only once,
+// which makes it suitable for single-use iterators.
+// For floating-point numbers, MinMax propagates NaNs
+// (any NaN value in seq forces both outputs to be NaN).
+// MinMax terminates if and only if seq is finite.
+func MinMax[E cmp.Ordered](seq iter.Seq[E]) (lo, hi E, ok bool) {
+	for e := range seq {
+		if !ok {
+			lo, hi, ok = e, e, true
+			continue
+		}
+		lo = min(e, lo)
+		hi = max(e, hi)
+	}
+	return lo, hi, ok
+}
+
+// MinMaxFunc, if seq is not empty, returns the minimal and maximal values
+// (using cmp as comparison function) in seq and true;
+// otherwise, it returns two zero values and false.
+// If there is more than one minimal (resp. maximal) element according
+// to the cmp function, MinMaxFunc returns the first one.
+// Unlike calling both [MinFunc] and [MaxFunc], MinMaxFunc ranges over seq
+// only once, which makes it suitable for single-use iterators.
+// MinMaxFunc terminates if and only if seq is finite.
+func MinMaxFunc[E any](seq iter.Seq[E], cmp func(E, E) int) (lo, hi E, ok bool) {
+	for e := range seq {
+		if !ok {
+			lo, hi, ok = e, e, true
+			continue
+		}
+		if cmp(e, lo) < 0 {
+			lo = e
+		} else if cmp(e, hi) > 0 {
+			hi = e
+		}
+	}
+	return lo, hi, ok
+}
+
+// ArgMin returns the index of the first minimal value in seq,
+// or -1 if seq is empty.
+// Values are compared with [cmp.Compare];
+// in particular, a NaN is considered less than any other value.
+// ArgMin terminates if and only if seq is finite.
+func ArgMin[E cmp.Ordered](seq iter.Seq[E]) int {
+	return argExtreme(seq, -1)
+}
+
+// ArgMax returns the index of the first maximal value in seq,
+// or -1 if seq is empty.
+// Values are compared with [cmp.Compare];
+// in particular, a NaN is considered less than any other value.
+// ArgMax terminates if and only if seq is finite.
+func ArgMax[E cmp.Ordered](seq iter.Seq[E]) int {
+	return argExtreme(seq, +1)
+}
+
+// argExtreme returns the index of the first minimal (if sign is -1)
+// or maximal (if sign is +1) element of seq, or -1 if seq is empty.
+func argExtreme[E cmp.Ordered](seq iter.Seq[E], sign int) int {
+	var (
+		m   E
+		arg = -1
+		i   int
+	)
+	for e := range seq {
+		if arg < 0 || cmp.Compare(e, m) == sign {
+			m, arg = e, i
+		}
+		i++
+	}
+	return arg
+}
+
+// MinBy, if seq is not empty, returns the first element of seq
+// whose key (as computed by key) is minimal and true;
+// otherwise, it returns the zero value and false.
+// MinBy calls key exactly once per element.
+// Keys are compared with [cmp.Compare];
+// in particular, a NaN key is considered less than any other key.
+// MinBy terminates if and only if seq is finite.
+func MinBy[E any, K cmp.Ordered](seq iter.Seq[E], key func(E) K) (E, bool) {
+	return extremeBy(seq, key, -1)
+}
+
+// MaxBy, if seq is not empty, returns the first element of seq
+// whose key (as computed by key) is maximal and true;
+// otherwise, it returns the zero value and false.
+// MaxBy calls key exactly once per element.
+// Keys are compared with [cmp.Compare];
+// in particular, a NaN key is considered less than any other key.
+// MaxBy terminates if and only if seq is finite.
+func MaxBy[E any, K cmp.Ordered](seq iter.Seq[E], key func(E) K) (E, bool) {
+	return extremeBy(seq, key, +1)
+}
+
+func extremeBy[E any, K cmp.Ordered](seq iter.Seq[E], key func(E) K, sign int) (E, bool) {
+	var (
+		m         E
+		mk        K
+		firstSeen bool
+	)
+	for e := range seq {
+		k := key(e)
+		if !firstSeen || cmp.Compare(k, mk) == sign {
+			m, mk, firstSeen = e, k, true
+		}
+	}
+	return m, firstSeen
+}
+
+// Min2, if seq is not empty, returns the first pair of seq whose key is
+// minimal and true; otherwise, it returns two zero values and false.
+// Keys are compared with [cmp.Compare];
+// in particular, a NaN key is considered less than any other key.
+// To find the pair whose value is minimal, use Min2(Swap(seq)).
+// Min2 terminates if and only if seq is finite.
+func Min2[K cmp.Ordered, V any](seq iter.Seq2[K, V]) (K, V, bool) {
+	return extreme2(seq, -1)
+}
+
+// Max2, if seq is not empty, returns the first pair of seq whose key is
+// maximal and true; otherwise, it returns two zero values and false.
+// Keys are compared with [cmp.Compare];
+// in particular, a NaN key is considered less than any other key.
+// To find the pair whose value is maximal, use Max2(Swap(seq)).
+// Max2 terminates if and only if seq is finite.
+func Max2[K cmp.Ordered, V any](seq iter.Seq2[K, V]) (K, V, bool) {
+	return extreme2(seq, +1)
+}
+
+func extreme2[K cmp.Ordered, V any](seq iter.Seq2[K, V], sign int) (K, V, bool) {
+	var (
+		mk        K
+		mv        V
+		firstSeen bool
+	)
+	for k, v := range seq {
+		if !firstSeen || cmp.Compare(k, mk) == sign {
+			mk, mv, firstSeen = k, v, true
+		}
+	}
+	return mk, mv, firstSeen
 }
 
 // Compare compares the elements of seq1 and seq2,
@@ -289,3 +821,540 @@ func Len2[K, V any](seq iter.Seq2[K, V]) int {
 	}
 	return n
 }
+
+// Sample returns a uniformly random sample, in no particular order,
+// of min(max(k, 0), Len(seq)) elements of seq,
+// using rng as source of randomness.
+// Sample ranges over seq only once and retains at most k elements,
+// which makes it suitable for single-use iterators.
+// It terminates if and only if seq is finite.
+func Sample[I constraints.Integer, E any](seq iter.Seq[E], k I, rng *rand.Rand) []E {
+	if k <= 0 {
+		return nil
+	}
+	// See https://en.wikipedia.org/wiki/Reservoir_sampling#Simple:_Algorithm_R.
+	var (
+		res []E
+		n   uint64 // number of elements seen so far
+	)
+	for e := range seq {
+		n++
+		if uint64(len(res)) < uint64(k) {
+			res = append(res, e)
+			continue
+		}
+		if j := rng.Uint64N(n); j < uint64(k) {
+			res[j] = e
+		}
+	}
+	return res
+}
+
+// SampleWeighted returns a weighted random sample, in no particular order,
+// of at most max(k, 0) elements of seq,
+// using weight to compute the weight of each element
+// and rng as source of randomness.
+// Each element is selected with a probability proportional to its weight;
+// elements whose weight is not positive are never selected.
+// SampleWeighted ranges over seq only once and retains at most k elements,
+// which makes it suitable for single-use iterators.
+// It terminates if and only if seq is finite.
+func SampleWeighted[I constraints.Integer, E any](
+	seq iter.Seq[E],
+	k I,
+	weight func(E) float64,
+	rng *rand.Rand,
+) []E {
+	if k <= 0 {
+		return nil
+	}
+	// We use Efraimidis and Spirakis's A-Res algorithm
+	// (see https://doi.org/10.1016/j.ipl.2005.11.003):
+	// each element e is assigned the key u^(1/weight(e)),
+	// where u is drawn uniformly from [0,1),
+	// and the k elements of greatest keys are retained in a min-heap.
+	// To avoid underflow, we work with logarithms of keys.
+	type keyed struct {
+		e   E
+		key float64
+	}
+	cmpKeys := func(x, y keyed) int { return cmp.Compare(x.key, y.key) }
+	var (
+		res  []keyed
+		heap internal.HeapFunc[keyed]
+	)
+	for e := range seq {
+		w := weight(e)
+		if !(w > 0) { // also excludes NaN
+			continue
+		}
+		ke := keyed{e: e, key: math.Log(rng.Float64()) / w}
+		if uint64(len(res)) < uint64(k) {
+			res = append(res, ke)
+			if uint64(len(res)) == uint64(k) {
+				heap = internal.NewHeapFunc(res, cmpKeys)
+			}
+			continue
+		}
+		if ke.key > heap.Min().key {
+			heap.ReplaceMin(ke)
+		}
+	}
+	es := make([]E, len(res))
+	for i, ke := range res {
+		es[i] = ke.e
+	}
+	return es
+}
+
+// JoinStrings concatenates the elements of seq to create a single string,
+// as [strings.Join] would;
+// sep is placed between elements in the resulting string.
+// JoinStrings terminates if and only if seq is finite.
+func JoinStrings(seq iter.Seq[string], sep string) string {
+	var (
+		sb        strings.Builder
+		firstSeen bool
+	)
+	for s := range seq {
+		if firstSeen {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(s)
+		firstSeen = true
+	}
+	return sb.String()
+}
+
+// JoinFunc concatenates the results of applying format to each element of seq
+// to create a single string;
+// sep is placed between elements in the resulting string.
+// JoinFunc terminates if and only if seq is finite.
+func JoinFunc[E any](seq iter.Seq[E], sep string, format func(E) string) string {
+	return JoinStrings(Map(seq, format), sep)
+}
+
+// WriteTo writes the elements of seq to w, separated by sep,
+// and returns the number of bytes written.
+// WriteTo stops at and returns the first error encountered.
+// Because WriteTo may call w's Write method many times,
+// you may want to wrap w in a [bufio.Writer] beforehand.
+// WriteTo terminates if and only if seq is finite.
+func WriteTo(w io.Writer, seq iter.Seq[string], sep string) (int64, error) {
+	var (
+		total     int64
+		firstSeen bool
+	)
+	for s := range seq {
+		if firstSeen && sep != "" {
+			n, err := io.WriteString(w, sep)
+			total += int64(n)
+			if err != nil {
+				return total, err
+			}
+		}
+		firstSeen = true
+		n, err := io.WriteString(w, s)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// AppendBytes appends the elements of seq, separated by sep, to dst
+// and returns the extended buffer.
+// AppendBytes terminates if and only if seq is finite.
+func AppendBytes(dst []byte, seq iter.Seq[string], sep string) []byte {
+	var firstSeen bool
+	for s := range seq {
+		if firstSeen {
+			dst = append(dst, sep...)
+		}
+		dst = append(dst, s...)
+		firstSeen = true
+	}
+	return dst
+}
+
+// Sum, if seq is not empty, returns the sum of the elements of seq and true;
+// otherwise, it returns 0 and false.
+// For integers, the result silently wraps around on overflow.
+// For floating-point numbers, see also [SumFloat].
+// Sum terminates if and only if seq is finite.
+func Sum[E constraints.Integer | constraints.Float](seq iter.Seq[E]) (E, bool) {
+	var (
+		sum       E
+		firstSeen bool
+	)
+	for e := range seq {
+		sum += e
+		firstSeen = true
+	}
+	return sum, firstSeen
+}
+
+// SumFunc, if seq is not empty, returns the sum of f(e)
+// for each element e of seq and true;
+// otherwise, it returns 0 and false.
+// SumFunc terminates if and only if seq is finite.
+func SumFunc[E any, N constraints.Integer | constraints.Float](seq iter.Seq[E], f func(E) N) (N, bool) {
+	var (
+		sum       N
+		firstSeen bool
+	)
+	for e := range seq {
+		sum += f(e)
+		firstSeen = true
+	}
+	return sum, firstSeen
+}
+
+// SumFloat, if seq is not empty, returns the sum of the elements of seq
+// and true; otherwise, it returns 0 and false.
+// Unlike [Sum], SumFloat uses [compensated summation], which
+// drastically reduces the rounding error that accumulates when
+// many floating-point numbers (especially of varied magnitudes) are added.
+// SumFloat terminates if and only if seq is finite.
+//
+// [compensated summation]: https://en.wikipedia.org/wiki/Kahan_summation_algorithm#Further_enhancements
+func SumFloat[F constraints.Float](seq iter.Seq[F]) (F, bool) {
+	var (
+		sum, c    F // c compensates for lost low-order bits
+		firstSeen bool
+	)
+	for x := range seq {
+		firstSeen = true
+		t := sum + x
+		if abs(sum) >= abs(x) {
+			c += (sum - t) + x
+		} else {
+			c += (x - t) + sum
+		}
+		sum = t
+	}
+	if math.IsInf(float64(sum), 0) { // c is then NaN
+		return sum, true
+	}
+	return sum + c, firstSeen
+}
+
+func abs[F constraints.Float](x F) F {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Prod, if seq is not empty, returns the product of the elements of seq
+// and true; otherwise, it returns 0 and false.
+// For integers, the result silently wraps around on overflow.
+// Prod terminates if and only if seq is finite.
+func Prod[E constraints.Integer | constraints.Float](seq iter.Seq[E]) (E, bool) {
+	var (
+		prod      E
+		firstSeen bool
+	)
+	for e := range seq {
+		if !firstSeen {
+			prod = e
+			firstSeen = true
+			continue
+		}
+		prod *= e
+	}
+	return prod, firstSeen
+}
+
+// Mean, if seq is not empty, returns the arithmetic mean of the elements
+// of seq and true; otherwise, it returns 0 and false.
+// Mean computes a running mean, which,
+// contrary to dividing the sum of the elements by their number,
+// is not susceptible to overflow.
+// Mean terminates if and only if seq is finite.
+func Mean[E constraints.Integer | constraints.Float](seq iter.Seq[E]) (float64, bool) {
+	var (
+		n    int
+		mean float64
+	)
+	for e := range seq {
+		n++
+		mean += (float64(e) - mean) / float64(n)
+	}
+	return mean, n > 0
+}
+
+// Variance, if seq is not empty, returns the population variance
+// of the elements of seq and true; otherwise, it returns 0 and false.
+// Variance relies on [Welford's algorithm],
+// which requires a single pass over seq and is numerically stable.
+// Variance terminates if and only if seq is finite.
+//
+// [Welford's algorithm]: https://en.wikipedia.org/wiki/Algorithms_for_calculating_variance#Welford's_online_algorithm
+func Variance[E constraints.Integer | constraints.Float](seq iter.Seq[E]) (float64, bool) {
+	var (
+		n        int
+		mean, m2 float64 // m2: sum of squared deviations from the mean
+	)
+	for e := range seq {
+		n++
+		x := float64(e)
+		d := x - mean
+		mean += d / float64(n)
+		m2 += d * (x - mean)
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return m2 / float64(n), true
+}
+
+// StdDev, if seq is not empty, returns the population standard deviation
+// of the elements of seq and true; otherwise, it returns 0 and false.
+// See [Variance] for more details.
+// StdDev terminates if and only if seq is finite.
+func StdDev[E constraints.Integer | constraints.Float](seq iter.Seq[E]) (float64, bool) {
+	v, ok := Variance(seq)
+	return math.Sqrt(v), ok
+}
+
+// Quantiles, if seq is not empty, returns, for each q in qs,
+// the q-quantile of the elements of seq (as computed by
+// the [nearest-rank method], i.e. the element of rank ceil(q*n),
+// or the minimal element if q is 0, where n is the number of elements);
+// otherwise, it returns nil.
+// Quantiles panics if any q in qs lies outside the range [0, 1].
+// Elements are compared with [cmp.Less];
+// in particular, a NaN is considered less than any other value.
+// Quantiles collects all the elements of seq and selects the desired ones
+// in expected linear time; see [QuantilesApprox] for a bounded-memory
+// alternative.
+// Quantiles terminates if and only if seq is finite.
+//
+// [nearest-rank method]: https://en.wikipedia.org/wiki/Percentile#The_nearest-rank_method
+func Quantiles[E cmp.Ordered](seq iter.Seq[E], qs ...float64) []E {
+	checkQuantiles(qs)
+	s := slices.Collect(seq)
+	if len(s) == 0 {
+		return nil
+	}
+	// Select the desired ranks in ascending order so that
+	// each selection only needs to consider the elements
+	// not smaller than the previously selected one.
+	order := make([]int, len(qs))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int { return cmp.Compare(qs[i], qs[j]) })
+	res := make([]E, len(qs))
+	var lo int
+	for _, i := range order {
+		k := nearestRank(qs[i], len(s)) - 1
+		internal.Select(s[lo:], k-lo)
+		res[i] = s[k]
+		lo = k
+	}
+	return res
+}
+
+// QuantilesApprox, if seq is not empty, returns, for each q in qs,
+// an approximate q-quantile of the elements of seq;
+// otherwise, it returns nil.
+// More precisely, the rank of each resulting element differs from
+// the rank ceil(q*n) of the exact q-quantile (see [Quantiles])
+// by at most eps*n, where n is the number of elements in seq.
+// QuantilesApprox panics if eps lies outside the range (0, 1)
+// or if any q in qs lies outside the range [0, 1].
+// Elements are compared with [cmp.Less];
+// in particular, a NaN is considered less than any other value.
+//
+// Instead of collecting all the elements of seq,
+// QuantilesApprox maintains a [Greenwald-Khanna] summary, whose size
+// is proportional to 1/eps and grows only logarithmically with n;
+// for instance, with eps = 0.001, it typically retains
+// on the order of a thousand elements of seq, regardless of n.
+// QuantilesApprox terminates if and only if seq is finite.
+//
+// [Greenwald-Khanna]: https://doi.org/10.1145/375663.375670
+func QuantilesApprox[E cmp.Ordered](seq iter.Seq[E], eps float64, qs ...float64) []E {
+	if !(0 < eps && eps < 1) {
+		panic("eps must be in the range (0, 1)")
+	}
+	checkQuantiles(qs)
+	s := internal.NewGK[E](eps)
+	for e := range seq {
+		s.Insert(e)
+	}
+	if s.Len() == 0 {
+		return nil
+	}
+	res := make([]E, len(qs))
+	for i, q := range qs {
+		res[i] = s.Query(q)
+	}
+	return res
+}
+
+func checkQuantiles(qs []float64) {
+	for _, q := range qs {
+		if !(0 <= q && q <= 1) {
+			panic("quantile must be in the range [0, 1]")
+		}
+	}
+}
+
+// nearestRank returns the (one-based) nearest rank of the q-quantile
+// among n elements.
+func nearestRank(q float64, n int) int {
+	return max(int(math.Ceil(q*float64(n))), 1)
+}
+
+// Median, if seq is not empty, returns the median of the elements of seq
+// and true; otherwise, it returns 0 and false.
+// If the number of elements is even, the median is the mean of
+// the two middle elements.
+// Median collects all the elements of seq and
+// runs in expected linear time.
+// Median terminates if and only if seq is finite.
+func Median[E constraints.Integer | constraints.Float](seq iter.Seq[E]) (float64, bool) {
+	s := slices.Collect(seq)
+	n := len(s)
+	if n == 0 {
+		return 0, false
+	}
+	k := n / 2
+	internal.Select(s, k)
+	if n%2 != 0 {
+		return float64(s[k]), true
+	}
+	lower := slices.Max(s[:k]) // s[:k] holds the k smallest elements
+	return (float64(lower) + float64(s[k])) / 2, true
+}
+
+// Frequencies returns a map from each distinct element of seq
+// to its number of occurrences in seq.
+// Frequencies terminates if and only if seq is finite.
+func Frequencies[E comparable](seq iter.Seq[E]) map[E]int {
+	m := make(map[E]int)
+	for e := range seq {
+		m[e]++
+	}
+	return m
+}
+
+// MostCommon returns a slice of the min(max(k, 0), d) most common elements
+// of seq, where d is the number of distinct elements of seq,
+// and a slice of their respective numbers of occurrences;
+// elements are ordered from the most common to the least common,
+// and elements that are equally common are ordered by first occurrence in seq.
+// MostCommon retains the counts of all distinct elements of seq
+// but selects the k most common ones in O(d*log(k)) time.
+// MostCommon terminates if and only if seq is finite.
+func MostCommon[I constraints.Integer, E comparable](seq iter.Seq[E], k I) ([]E, []int) {
+	if k <= 0 {
+		return nil, nil
+	}
+	type entry struct {
+		elem  E
+		count int
+		first int // index of first occurrence
+	}
+	var (
+		entries []*entry
+		m       = make(map[E]*entry)
+		i       int
+	)
+	for e := range seq {
+		if ent, found := m[e]; found {
+			ent.count++
+		} else {
+			ent = &entry{elem: e, count: 1, first: i}
+			m[e] = ent
+			entries = append(entries, ent)
+		}
+		i++
+	}
+	// cmp orders entries from the most common to the least common.
+	cmp := func(a, b *entry) int {
+		if a.count != b.count {
+			return b.count - a.count
+		}
+		return a.first - b.first
+	}
+	if uint64(len(entries)) > uint64(k) {
+		// Keep the k most common entries in a bounded heap
+		// whose least element is the least common of them.
+		rev := func(a, b *entry) int { return cmp(b, a) }
+		h := internal.NewHeapFunc(slices.Clone(entries[:k]), rev)
+		for _, ent := range entries[k:] {
+			if cmp(ent, h.Min()) < 0 {
+				h.ReplaceMin(ent)
+			}
+		}
+		entries = entries[:0]
+		for ent := range h.Iterator {
+			entries = append(entries, ent)
+		}
+		slices.Reverse(entries)
+	} else {
+		slices.SortFunc(entries, cmp)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	elems := make([]E, len(entries))
+	counts := make([]int, len(entries))
+	for i, ent := range entries {
+		elems[i] = ent.elem
+		counts[i] = ent.count
+	}
+	return elems, counts
+}
+
+// Histogram returns the number of elements of seq that fall in each of
+// the len(buckets)+1 intervals delimited by buckets:
+// the element of index 0 of the result counts the elements less than
+// buckets[0]; the element of index i, for i in [1, len(buckets)),
+// counts the elements in [buckets[i-1], buckets[i]);
+// and the last element counts the elements not less than the last bucket.
+// Histogram panics if buckets is not sorted in strictly increasing order.
+// Elements are compared with [cmp.Compare];
+// in particular, a NaN is considered less than any other value.
+// Histogram terminates if and only if seq is finite.
+func Histogram[E cmp.Ordered](seq iter.Seq[E], buckets []E) []int {
+	for i := 1; i < len(buckets); i++ {
+		if cmp.Compare(buckets[i-1], buckets[i]) >= 0 {
+			panic("buckets must be sorted in strictly increasing order")
+		}
+	}
+	counts := make([]int, len(buckets)+1)
+	for e := range seq {
+		i, found := slices.BinarySearch(buckets, e)
+		if found {
+			i++
+		}
+		counts[i]++
+	}
+	return counts
+}
+
+// Mode, if seq is not empty, returns the most common element of seq and true;
+// otherwise, it returns the zero value and false.
+// If several elements are equally common,
+// Mode returns the one that first reached that number of occurrences.
+// Mode terminates if and only if seq is finite.
+func Mode[E comparable](seq iter.Seq[E]) (E, bool) {
+	var (
+		mode E
+		best int
+		m    = make(map[E]int)
+	)
+	for e := range seq {
+		m[e]++
+		if c := m[e]; c > best {
+			mode, best = e, c
+		}
+	}
+	return mode, best > 0
+}
@@ -1,11 +1,19 @@
 package iterutil_test
 
 import (
+	"bytes"
 	"cmp"
+	"errors"
 	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"math/rand/v2"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jub0bs/iterutil"
 )
@@ -371,3 +379,1398 @@ func ExampleLen2() {
 	// 0
 	// 4
 }
+
+func ExampleSample() {
+	seq := iterutil.Between(0, 1_000_000, 1)
+	rng := rand.New(rand.NewPCG(1, 2))
+	fmt.Println(iterutil.Sample(seq, 3, rng))
+	// Output:
+	// [813695 659487 745346]
+}
+
+func TestSample(t *testing.T) {
+	cases := []struct {
+		desc  string
+		elems []int
+		k     int
+		want  int // length of the sample
+	}{
+		{
+			desc:  "negative k",
+			elems: []int{0, 1, 2},
+			k:     -1,
+		}, {
+			desc:  "zero k",
+			elems: []int{0, 1, 2},
+		}, {
+			desc:  "fewer elements than k",
+			elems: []int{0, 1, 2},
+			k:     4,
+			want:  3,
+		}, {
+			desc:  "more elements than k",
+			elems: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			k:     4,
+			want:  4,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			got := iterutil.Sample(slices.Values(tc.elems), tc.k, rng)
+			if len(got) != tc.want {
+				t.Fatalf("got %v (of length %d); want length %d", got, len(got), tc.want)
+			}
+			slices.Sort(got)
+			if len(slices.Compact(slices.Clone(got))) != len(got) {
+				t.Fatalf("got %v; want distinct elements", got)
+			}
+			for _, e := range got {
+				if !slices.Contains(tc.elems, e) {
+					t.Fatalf("got %v; want elements of %v", got, tc.elems)
+				}
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestSampleUniformity(t *testing.T) {
+	const (
+		trials = 10000
+		n      = 10
+		k      = 3
+	)
+	rng := rand.New(rand.NewPCG(1, 2))
+	var counts [n]int
+	for range trials {
+		for _, e := range iterutil.Sample(iterutil.Between(0, n, 1), k, rng) {
+			counts[e]++
+		}
+	}
+	const want = trials * k / n
+	for e, c := range counts {
+		if c < want*9/10 || want*11/10 < c {
+			t.Errorf("element %d selected %d times; want about %d", e, c, want)
+		}
+	}
+}
+
+func ExampleSampleWeighted() {
+	seq := slices.Values([]string{"foo", "bar", "baz", "qux"})
+	weights := map[string]float64{"foo": 1, "bar": 0, "baz": 100, "qux": 100}
+	weight := func(s string) float64 { return weights[s] }
+	rng := rand.New(rand.NewPCG(1, 2))
+	fmt.Println(iterutil.SampleWeighted(seq, 2, weight, rng))
+	// Output:
+	// [baz qux]
+}
+
+func TestSampleWeighted(t *testing.T) {
+	const trials = 10000
+	elems := []string{"zero", "one", "two", "three", "NaN"}
+	weights := map[string]float64{
+		"zero":  0,
+		"one":   1,
+		"two":   2,
+		"three": 3,
+		"NaN":   math.NaN(),
+	}
+	weight := func(s string) float64 { return weights[s] }
+	rng := rand.New(rand.NewPCG(1, 2))
+	counts := make(map[string]int)
+	for range trials {
+		got := iterutil.SampleWeighted(slices.Values(elems), 1, weight, rng)
+		if len(got) != 1 {
+			t.Fatalf("got %v; want exactly one element", got)
+		}
+		counts[got[0]]++
+	}
+	for _, s := range elems {
+		var want int
+		if w := weights[s]; !math.IsNaN(w) {
+			want = int(trials * w / 6)
+		}
+		if c := counts[s]; c < want*9/10 || want*11/10 < c {
+			t.Errorf("element %q selected %d times; want about %d", s, c, want)
+		}
+	}
+	// k larger than the number of eligible elements
+	got := iterutil.SampleWeighted(slices.Values(elems), 10, weight, rng)
+	slices.Sort(got)
+	if want := []string{"one", "three", "two"}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	// non-positive k
+	if got := iterutil.SampleWeighted(slices.Values(elems), 0, weight, rng); got != nil {
+		t.Errorf("got %v; want nil", got)
+	}
+}
+
+func ExampleJoinStrings() {
+	seq := slices.Values([]string{"foo", "bar", "baz"})
+	fmt.Println(iterutil.JoinStrings(seq, ", "))
+	// Output:
+	// foo, bar, baz
+}
+
+func TestJoinStrings(t *testing.T) {
+	cases := []struct {
+		desc  string
+		elems []string
+		sep   string
+	}{
+		{
+			desc: "empty",
+			sep:  ", ",
+		}, {
+			desc:  "one element",
+			elems: []string{"foo"},
+			sep:   ", ",
+		}, {
+			desc:  "several elements",
+			elems: []string{"foo", "", "baz"},
+			sep:   ", ",
+		}, {
+			desc:  "empty separator",
+			elems: []string{"foo", "bar", "baz"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			want := strings.Join(tc.elems, tc.sep)
+			if got := iterutil.JoinStrings(seq, tc.sep); got != want {
+				t.Errorf("JoinStrings: got %q; want %q", got, want)
+			}
+			dst := []byte("prefix:")
+			got := string(iterutil.AppendBytes(dst, seq, tc.sep))
+			if want := "prefix:" + want; got != want {
+				t.Errorf("AppendBytes: got %q; want %q", got, want)
+			}
+			var sb strings.Builder
+			n, err := iterutil.WriteTo(&sb, seq, tc.sep)
+			if sb.String() != want || n != int64(len(want)) || err != nil {
+				const tmpl = "WriteTo: wrote %q and got %d, %v; want %q, %d, <nil>"
+				t.Errorf(tmpl, sb.String(), n, err, want, len(want))
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleJoinFunc() {
+	seq := slices.Values([]int{1, 2, 3})
+	fmt.Println(iterutil.JoinFunc(seq, " + ", strconv.Itoa))
+	// Output:
+	// 1 + 2 + 3
+}
+
+func ExampleWriteTo() {
+	seq := slices.Values([]string{"foo", "bar", "baz"})
+	var sb strings.Builder
+	n, err := iterutil.WriteTo(&sb, seq, "\n")
+	fmt.Println(sb.String())
+	fmt.Println(n, err)
+	// Output:
+	// foo
+	// bar
+	// baz
+	// 11 <nil>
+}
+
+func TestWriteToError(t *testing.T) {
+	cases := []struct {
+		desc  string
+		limit int
+		wantN int64
+	}{
+		{
+			desc:  "error on element",
+			limit: 6,
+			wantN: 6,
+		}, {
+			desc:  "error on separator",
+			limit: 4,
+			wantN: 4,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			w := &shortWriter{limit: tc.limit}
+			seq := slices.Values([]string{"foo", "bar", "baz"})
+			n, err := iterutil.WriteTo(w, seq, ", ")
+			if n != tc.wantN || !errors.Is(err, errShortWrite) {
+				const tmpl = "got %d, %v; want %d, %v"
+				t.Errorf(tmpl, n, err, tc.wantN, errShortWrite)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+var errShortWrite = errors.New("short write")
+
+// shortWriter accepts up to limit bytes and then fails with errShortWrite.
+type shortWriter struct {
+	limit int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errShortWrite
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func ExampleSum() {
+	fmt.Println(iterutil.Sum(iterutil.Empty[int]()))
+	fmt.Println(iterutil.Sum(iterutil.SeqOf(3, 5, 1, 42)))
+	// Output:
+	// 0 false
+	// 51 true
+}
+
+func ExampleSumFunc() {
+	words := iterutil.SeqOf("foo", "quux", "baz")
+	fmt.Println(iterutil.SumFunc(words, func(s string) int { return len(s) }))
+	// Output:
+	// 10 true
+}
+
+func ExampleSumFloat() {
+	seq := iterutil.SeqOf(1, 1e100, 1, -1e100)
+	fmt.Println(iterutil.Sum(seq))
+	fmt.Println(iterutil.SumFloat(seq))
+	// Output:
+	// 0 true
+	// 2 true
+}
+
+func TestSumFloat(t *testing.T) {
+	cases := []struct {
+		desc   string
+		elems  []float64
+		want   float64
+		wantOK bool
+	}{
+		{
+			desc: "empty",
+		}, {
+			desc:   "small values",
+			elems:  []float64{0.1, 0.2, 0.3},
+			want:   0.6,
+			wantOK: true,
+		}, {
+			desc:   "cancellation",
+			elems:  []float64{1, 1e100, 1, -1e100},
+			want:   2,
+			wantOK: true,
+		}, {
+			desc:   "many small values",
+			elems:  slices.Repeat([]float64{0.1}, 1000),
+			want:   100,
+			wantOK: true,
+		}, {
+			desc:   "infinity",
+			elems:  []float64{1, math.Inf(1), 2},
+			want:   math.Inf(1),
+			wantOK: true,
+		}, {
+			desc:   "negative infinity",
+			elems:  []float64{math.Inf(-1), 1},
+			want:   math.Inf(-1),
+			wantOK: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got, ok := iterutil.SumFloat(slices.Values(tc.elems))
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("got %v, %t; want %v, %t", got, ok, tc.want, tc.wantOK)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleProd() {
+	fmt.Println(iterutil.Prod(iterutil.Empty[int]()))
+	fmt.Println(iterutil.Prod(iterutil.Between(1, 6, 1)))
+	// Output:
+	// 0 false
+	// 120 true
+}
+
+func ExampleMean() {
+	fmt.Println(iterutil.Mean(iterutil.Empty[int]()))
+	fmt.Println(iterutil.Mean(iterutil.SeqOf(3, 5, 1, 42)))
+	// Output:
+	// 0 false
+	// 12.75 true
+}
+
+func ExampleVariance() {
+	fmt.Println(iterutil.Variance(iterutil.Empty[int]()))
+	fmt.Println(iterutil.Variance(iterutil.SeqOf(2, 4, 4, 4, 5, 5, 7, 9)))
+	// Output:
+	// 0 false
+	// 4 true
+}
+
+func ExampleStdDev() {
+	fmt.Println(iterutil.StdDev(iterutil.Empty[int]()))
+	fmt.Println(iterutil.StdDev(iterutil.SeqOf(2, 4, 4, 4, 5, 5, 7, 9)))
+	// Output:
+	// 0 false
+	// 2 true
+}
+
+func TestMeanVariance(t *testing.T) {
+	cases := []struct {
+		desc         string
+		elems        []float64
+		wantMean     float64
+		wantVariance float64
+	}{
+		{
+			desc:     "one element",
+			elems:    []float64{42},
+			wantMean: 42,
+		}, {
+			desc:         "several elements",
+			elems:        []float64{1, 2, 3, 4},
+			wantMean:     2.5,
+			wantVariance: 1.25,
+		}, {
+			// a naive sum of squares would lose all precision here
+			desc:         "large offset",
+			elems:        []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16},
+			wantMean:     1e9 + 10,
+			wantVariance: 22.5,
+		}, {
+			desc:         "overflow-prone values",
+			elems:        []float64{math.MaxFloat64, math.MaxFloat64},
+			wantMean:     math.MaxFloat64,
+			wantVariance: 0,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			mean, ok := iterutil.Mean(seq)
+			if !ok || mean != tc.wantMean {
+				t.Errorf("Mean: got %v, %t; want %v, true", mean, ok, tc.wantMean)
+			}
+			v, ok := iterutil.Variance(seq)
+			if !ok || math.Abs(v-tc.wantVariance) > 1e-9 {
+				t.Errorf("Variance: got %v, %t; want %v, true", v, ok, tc.wantVariance)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestMaxNegative(t *testing.T) {
+	seq := iterutil.SeqOf(-3, -1, -2)
+	if got, ok := iterutil.Max(seq); got != -1 || !ok {
+		t.Errorf("Max: got %d, %t; want -1, true", got, ok)
+	}
+	if got, ok := iterutil.MaxFunc(seq, cmp.Compare); got != -1 || !ok {
+		t.Errorf("MaxFunc: got %d, %t; want -1, true", got, ok)
+	}
+}
+
+func ExampleMinMax() {
+	seq := slices.Values([]int(nil))
+	fmt.Println(iterutil.MinMax(seq))
+	seq = slices.Values([]int{3, 5, 1, 42})
+	fmt.Println(iterutil.MinMax(seq))
+	// Output:
+	// 0 0 false
+	// 1 42 true
+}
+
+func ExampleMinMaxFunc() {
+	seq := slices.Values([]string{"foo", "quux", "ab", "bar"})
+	byLen := func(a, b string) int { return cmp.Compare(len(a), len(b)) }
+	fmt.Println(iterutil.MinMaxFunc(seq, byLen))
+	// Output:
+	// ab quux true
+}
+
+func TestMinMax(t *testing.T) {
+	cases := []struct {
+		desc   string
+		elems  []float64
+		wantLo float64
+		wantHi float64
+		wantOK bool
+	}{
+		{
+			desc: "empty",
+		}, {
+			desc:   "one element",
+			elems:  []float64{-1},
+			wantLo: -1,
+			wantHi: -1,
+			wantOK: true,
+		}, {
+			desc:   "negative values",
+			elems:  []float64{-3, -1, -2},
+			wantLo: -3,
+			wantHi: -1,
+			wantOK: true,
+		}, {
+			desc:   "several elements",
+			elems:  []float64{3, 5, 1, 42, 7},
+			wantLo: 1,
+			wantHi: 42,
+			wantOK: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			lo, hi, ok := iterutil.MinMax(seq)
+			if lo != tc.wantLo || hi != tc.wantHi || ok != tc.wantOK {
+				const tmpl = "MinMax: got %v, %v, %t; want %v, %v, %t"
+				t.Errorf(tmpl, lo, hi, ok, tc.wantLo, tc.wantHi, tc.wantOK)
+			}
+			lo, hi, ok = iterutil.MinMaxFunc(seq, cmp.Compare)
+			if lo != tc.wantLo || hi != tc.wantHi || ok != tc.wantOK {
+				const tmpl = "MinMaxFunc: got %v, %v, %t; want %v, %v, %t"
+				t.Errorf(tmpl, lo, hi, ok, tc.wantLo, tc.wantHi, tc.wantOK)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+	// NaN propagation
+	lo, hi, _ := iterutil.MinMax(iterutil.SeqOf(1, math.NaN(), 2))
+	if !math.IsNaN(lo) || !math.IsNaN(hi) {
+		t.Errorf("MinMax: got %v, %v; want NaN, NaN", lo, hi)
+	}
+}
+
+func ExampleArgMin() {
+	fmt.Println(iterutil.ArgMin(iterutil.Empty[int]()))
+	fmt.Println(iterutil.ArgMin(iterutil.SeqOf(3, 1, 5, 1)))
+	// Output:
+	// -1
+	// 1
+}
+
+func ExampleArgMax() {
+	fmt.Println(iterutil.ArgMax(iterutil.Empty[int]()))
+	fmt.Println(iterutil.ArgMax(iterutil.SeqOf(3, 5, 1, 5)))
+	// Output:
+	// -1
+	// 1
+}
+
+func TestArgMinArgMax(t *testing.T) {
+	cases := []struct {
+		desc    string
+		elems   []int
+		wantMin int
+		wantMax int
+	}{
+		{
+			desc:    "empty",
+			wantMin: -1,
+			wantMax: -1,
+		}, {
+			desc:  "one element",
+			elems: []int{-7},
+		}, {
+			desc:    "negative values",
+			elems:   []int{-3, -1, -2},
+			wantMin: 0,
+			wantMax: 1,
+		}, {
+			desc:    "ties",
+			elems:   []int{2, 1, 3, 1, 3},
+			wantMin: 1,
+			wantMax: 2,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			if got := iterutil.ArgMin(seq); got != tc.wantMin {
+				t.Errorf("ArgMin: got %d; want %d", got, tc.wantMin)
+			}
+			if got := iterutil.ArgMax(seq); got != tc.wantMax {
+				t.Errorf("ArgMax: got %d; want %d", got, tc.wantMax)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleMinBy() {
+	seq := slices.Values([]string{"foo", "quux", "ab", "cd"})
+	fmt.Println(iterutil.MinBy(seq, func(s string) int { return len(s) }))
+	// Output:
+	// ab true
+}
+
+func ExampleMaxBy() {
+	seq := slices.Values([]string{"foo", "quux", "ab", "baz"})
+	fmt.Println(iterutil.MaxBy(seq, func(s string) int { return len(s) }))
+	// Output:
+	// quux true
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	cases := []struct {
+		desc    string
+		elems   []string
+		wantMin string
+		wantMax string
+		wantOK  bool
+	}{
+		{
+			desc: "empty",
+		}, {
+			desc:    "one element",
+			elems:   []string{"foo"},
+			wantMin: "foo",
+			wantMax: "foo",
+			wantOK:  true,
+		}, {
+			desc:    "ties",
+			elems:   []string{"foo", "ab", "cd", "quux", "abcd"},
+			wantMin: "ab",
+			wantMax: "quux",
+			wantOK:  true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var calls int
+			key := func(s string) int {
+				calls++
+				return len(s)
+			}
+			seq := slices.Values(tc.elems)
+			got, ok := iterutil.MinBy(seq, key)
+			if got != tc.wantMin || ok != tc.wantOK {
+				const tmpl = "MinBy: got %q, %t; want %q, %t"
+				t.Errorf(tmpl, got, ok, tc.wantMin, tc.wantOK)
+			}
+			got, ok = iterutil.MaxBy(seq, key)
+			if got != tc.wantMax || ok != tc.wantOK {
+				const tmpl = "MaxBy: got %q, %t; want %q, %t"
+				t.Errorf(tmpl, got, ok, tc.wantMax, tc.wantOK)
+			}
+			if want := 2 * len(tc.elems); calls != want {
+				t.Errorf("got %d calls to key; want %d", calls, want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleMin2() {
+	ages := iterutil.Zip(
+		iterutil.SeqOf("alice", "bob", "carol"),
+		iterutil.SeqOf(31, 27, 45),
+	)
+	fmt.Println(iterutil.Min2(ages))
+	fmt.Println(iterutil.Min2(iterutil.Swap(ages)))
+	// Output:
+	// alice 31 true
+	// 27 bob true
+}
+
+func ExampleMax2() {
+	ages := iterutil.Zip(
+		iterutil.SeqOf("alice", "bob", "carol"),
+		iterutil.SeqOf(31, 27, 45),
+	)
+	fmt.Println(iterutil.Max2(ages))
+	fmt.Println(iterutil.Max2(iterutil.Swap(ages)))
+	// Output:
+	// carol 45 true
+	// 45 carol true
+}
+
+func TestMin2Max2(t *testing.T) {
+	cases := []struct {
+		desc    string
+		elems   []string
+		wantMin Pair[string, int]
+		wantMax Pair[string, int]
+		wantOK  bool
+	}{
+		{
+			desc: "empty",
+		}, {
+			desc:    "one element",
+			elems:   []string{"foo"},
+			wantMin: Pair[string, int]{"foo", 0},
+			wantMax: Pair[string, int]{"foo", 0},
+			wantOK:  true,
+		}, {
+			desc:    "ties",
+			elems:   []string{"b", "a", "c", "a", "c"},
+			wantMin: Pair[string, int]{"a", 1},
+			wantMax: Pair[string, int]{"c", 2},
+			wantOK:  true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := iterutil.Swap(slices.All(tc.elems))
+			k, v, ok := iterutil.Min2(seq)
+			if got := (Pair[string, int]{k, v}); got != tc.wantMin || ok != tc.wantOK {
+				const tmpl = "Min2: got %v, %t; want %v, %t"
+				t.Errorf(tmpl, got, ok, tc.wantMin, tc.wantOK)
+			}
+			k, v, ok = iterutil.Max2(seq)
+			if got := (Pair[string, int]{k, v}); got != tc.wantMax || ok != tc.wantOK {
+				const tmpl = "Max2: got %v, %t; want %v, %t"
+				t.Errorf(tmpl, got, ok, tc.wantMax, tc.wantOK)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleQuantiles() {
+	latencies := iterutil.SeqOf(12, 7, 3, 45, 9, 15, 21, 8, 10, 5)
+	fmt.Println(iterutil.Quantiles(latencies, 0.5, 0.9, 0.99))
+	// Output:
+	// [9 21 45]
+}
+
+func TestQuantiles(t *testing.T) {
+	cases := []struct {
+		desc  string
+		elems []int
+		qs    []float64
+		want  []int
+	}{
+		{
+			desc: "empty",
+			qs:   []float64{0.5},
+		}, {
+			desc:  "no quantiles",
+			elems: []int{3, 1, 2},
+			want:  []int{},
+		}, {
+			desc:  "one element",
+			elems: []int{42},
+			qs:    []float64{0, 0.5, 1},
+			want:  []int{42, 42, 42},
+		}, {
+			desc:  "extremes",
+			elems: []int{3, 1, 4, 1, 5, 9, 2, 6},
+			qs:    []float64{1, 0},
+			want:  []int{9, 1},
+		}, {
+			desc:  "unsorted quantiles",
+			elems: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			qs:    []float64{0.9, 0.15, 0.5, 0.5, 0.11},
+			want:  []int{9, 2, 5, 5, 2},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.Quantiles(slices.Values(tc.elems), tc.qs...)
+			if !slices.Equal(got, tc.want) || (got == nil) != (tc.want == nil) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestQuantilesPanic(t *testing.T) {
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		f := func() { iterutil.Quantiles(iterutil.SeqOf(1, 2, 3), q) }
+		assertPanics(t, f, "quantile must be in the range [0, 1]")
+		g := func() { iterutil.QuantilesApprox(iterutil.SeqOf(1, 2, 3), 0.01, q) }
+		assertPanics(t, g, "quantile must be in the range [0, 1]")
+	}
+	for _, eps := range []float64{0, 1, -1, math.NaN()} {
+		f := func() { iterutil.QuantilesApprox(iterutil.SeqOf(1, 2, 3), eps, 0.5) }
+		assertPanics(t, f, "eps must be in the range (0, 1)")
+	}
+}
+
+func TestQuantilesManyDuplicates(t *testing.T) {
+	// e.g. latencies measured in whole milliseconds
+	const (
+		n       = 1 << 17
+		timeout = time.Second
+	)
+	fewDistinct := make([]int, n)
+	for i := range fewDistinct {
+		fewDistinct[i] = rand.IntN(5)
+	}
+	cases := []struct {
+		desc  string
+		elems []int
+	}{
+		{
+			desc:  "all equal",
+			elems: make([]int, n),
+		}, {
+			desc:  "few distinct",
+			elems: fewDistinct,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			sorted := slices.Sorted(slices.Values(tc.elems))
+			qs := []float64{0.5, 0.9, 0.99}
+			start := time.Now()
+			got := iterutil.Quantiles(slices.Values(tc.elems), qs...)
+			if d := time.Since(start); d > timeout {
+				t.Errorf("Quantiles took %v; want at most %v", d, timeout)
+			}
+			want := make([]int, len(qs))
+			for i, q := range qs {
+				want[i] = sorted[int(math.Ceil(q*n))-1]
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("Quantiles: got %v; want %v", got, want)
+			}
+			start = time.Now()
+			median, _ := iterutil.Median(slices.Values(tc.elems))
+			if d := time.Since(start); d > timeout {
+				t.Errorf("Median took %v; want at most %v", d, timeout)
+			}
+			if want := float64(sorted[n/2-1]+sorted[n/2]) / 2; median != want {
+				t.Errorf("Median: got %v; want %v", median, want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleQuantilesApprox() {
+	// 1, 2, ..., 1_000_000
+	seq := iterutil.Between(1, 1_000_001, 1)
+	const eps = 0.001 // rank error of at most 0.001 * 1_000_000 = 1000
+	qs := []float64{0.5, 0.99}
+	for i, v := range iterutil.QuantilesApprox(seq, eps, qs...) {
+		exact := int(qs[i] * 1_000_000)
+		fmt.Println(exact-1000 <= v && v <= exact+1000)
+	}
+	// Output:
+	// true
+	// true
+}
+
+func TestQuantilesApprox(t *testing.T) {
+	const n = 100_000
+	elems := make([]float64, n)
+	for i, v := range rand.Perm(n) {
+		elems[i] = float64(v)
+	}
+	qs := []float64{0, 0.25, 0.5, 0.75, 0.9, 0.99, 1}
+	exact := iterutil.Quantiles(slices.Values(elems), qs...)
+	for _, eps := range []float64{0.1, 0.01, 0.001} {
+		got := iterutil.QuantilesApprox(slices.Values(elems), eps, qs...)
+		for i := range qs {
+			if math.Abs(got[i]-exact[i]) > eps*n {
+				const tmpl = "eps=%v, q=%v: got %v; want %v ± %v"
+				t.Errorf(tmpl, eps, qs[i], got[i], exact[i], eps*n)
+			}
+		}
+	}
+	if got := iterutil.QuantilesApprox(iterutil.Empty[int](), 0.1, 0.5); got != nil {
+		t.Errorf("got %v; want nil", got)
+	}
+}
+
+func ExampleMedian() {
+	fmt.Println(iterutil.Median(iterutil.Empty[int]()))
+	fmt.Println(iterutil.Median(iterutil.SeqOf(3, 5, 1)))
+	fmt.Println(iterutil.Median(iterutil.SeqOf(3, 5, 1, 42)))
+	// Output:
+	// 0 false
+	// 3 true
+	// 4 true
+}
+
+func ExampleFrequencies() {
+	words := strings.Fields("the cat and the hat and the bat")
+	freqs := iterutil.Frequencies(slices.Values(words))
+	for _, w := range slices.Sorted(maps.Keys(freqs)) {
+		fmt.Println(w, freqs[w])
+	}
+	// Output:
+	// and 2
+	// bat 1
+	// cat 1
+	// hat 1
+	// the 3
+}
+
+func ExampleMostCommon() {
+	words := strings.Fields("the cat and the hat and the bat")
+	elems, counts := iterutil.MostCommon(slices.Values(words), 3)
+	for i, w := range elems {
+		fmt.Println(w, counts[i])
+	}
+	// Output:
+	// the 3
+	// and 2
+	// cat 1
+}
+
+func TestMostCommon(t *testing.T) {
+	cases := []struct {
+		desc       string
+		elems      string
+		k          int
+		wantElems  []string
+		wantCounts []int
+	}{
+		{
+			desc: "empty",
+			k:    3,
+		}, {
+			desc:  "negative k",
+			elems: "a b a",
+			k:     -1,
+		}, {
+			desc:  "zero k",
+			elems: "a b a",
+			k:     0,
+		}, {
+			desc:       "k greater than number of distinct elements",
+			elems:      "c a b a b a",
+			k:          5,
+			wantElems:  []string{"a", "b", "c"},
+			wantCounts: []int{3, 2, 1},
+		}, {
+			desc:       "ties ordered by first occurrence",
+			elems:      "d c b a a b c d e e",
+			k:          3,
+			wantElems:  []string{"d", "c", "b"},
+			wantCounts: []int{2, 2, 2},
+		}, {
+			desc:       "late heavy hitter",
+			elems:      "a b c d e f g g g f",
+			k:          2,
+			wantElems:  []string{"g", "f"},
+			wantCounts: []int{3, 2},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(strings.Fields(tc.elems))
+			elems, counts := iterutil.MostCommon(seq, tc.k)
+			if !slices.Equal(elems, tc.wantElems) || !slices.Equal(counts, tc.wantCounts) {
+				const tmpl = "got %q, %v; want %q, %v"
+				t.Errorf(tmpl, elems, counts, tc.wantElems, tc.wantCounts)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleHistogram() {
+	latencies := iterutil.SeqOf(3, 12, 7, 150, 45, 9, 99, 100, 1)
+	fmt.Println(iterutil.Histogram(latencies, []int{10, 100}))
+	// Output:
+	// [4 3 2]
+}
+
+func TestHistogram(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []float64
+		buckets   []float64
+		want      []int
+		wantPanic bool
+	}{
+		{
+			desc: "no buckets",
+			want: []int{0},
+		}, {
+			desc:    "empty",
+			buckets: []float64{0, 1},
+			want:    []int{0, 0, 0},
+		}, {
+			desc:    "boundaries",
+			elems:   []float64{-1, 0, 0.5, 1, 2, math.NaN()},
+			buckets: []float64{0, 1},
+			want:    []int{2, 2, 2},
+		}, {
+			desc:      "unsorted buckets",
+			buckets:   []float64{1, 0},
+			wantPanic: true,
+		}, {
+			desc:      "duplicate buckets",
+			buckets:   []float64{0, 1, 1},
+			wantPanic: true,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			if tc.wantPanic {
+				f := func() { iterutil.Histogram(slices.Values(tc.elems), tc.buckets) }
+				assertPanics(t, f, "buckets must be sorted in strictly increasing order")
+				return
+			}
+			got := iterutil.Histogram(slices.Values(tc.elems), tc.buckets)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleMode() {
+	fmt.Println(iterutil.Mode(iterutil.Empty[int]()))
+	fmt.Println(iterutil.Mode(iterutil.SeqOf(1, 2, 3, 2, 1, 2)))
+	// Output:
+	// 0 false
+	// 2 true
+}
+
+func TestMode(t *testing.T) {
+	cases := []struct {
+		desc  string
+		elems string
+		want  string
+	}{
+		{
+			desc:  "one element",
+			elems: "a",
+			want:  "a",
+		}, {
+			desc:  "unique mode",
+			elems: "a b b c",
+			want:  "b",
+		}, {
+			desc:  "tie",
+			elems: "a b b a",
+			want:  "b",
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got, ok := iterutil.Mode(slices.Values(strings.Fields(tc.elems)))
+			if got != tc.want || !ok {
+				t.Errorf("got %q, %t; want %q, true", got, ok, tc.want)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleFirst() {
+	fmt.Println(iterutil.First(iterutil.Empty[int]()))
+	// First terminates even on infinite iterators.
+	fmt.Println(iterutil.First(iterutil.Iterate(1, func(i int) int { return 2 * i })))
+	// Output:
+	// 0 false
+	// 1 true
+}
+
+func ExampleLast() {
+	fmt.Println(iterutil.Last(iterutil.Empty[int]()))
+	fmt.Println(iterutil.Last(iterutil.SeqOf(3, 5, 1, 42)))
+	// Output:
+	// 0 false
+	// 42 true
+}
+
+func ExampleFind() {
+	isEven := func(i int) bool { return i%2 == 0 }
+	fmt.Println(iterutil.Find(iterutil.SeqOf(3, 5, 1), isEven))
+	fmt.Println(iterutil.Find(iterutil.Iterate(1, func(i int) int { return 3 * i }), func(i int) bool {
+		return i > 100
+	}))
+	// Output:
+	// 0 false
+	// 243 true
+}
+
+func ExampleIndex() {
+	seq := iterutil.SeqOf("foo", "bar", "baz", "bar")
+	fmt.Println(iterutil.Index(seq, "bar"))
+	fmt.Println(iterutil.Index(seq, "qux"))
+	// Output:
+	// 1
+	// -1
+}
+
+func ExampleIndexFunc() {
+	seq := iterutil.SeqOf(3, 5, 8, 1, 42)
+	isEven := func(i int) bool { return i%2 == 0 }
+	fmt.Println(iterutil.IndexFunc(seq, isEven))
+	// Output:
+	// 2
+}
+
+func ExampleLastIndex() {
+	seq := iterutil.SeqOf("foo", "bar", "baz", "bar")
+	fmt.Println(iterutil.LastIndex(seq, "bar"))
+	fmt.Println(iterutil.LastIndex(seq, "qux"))
+	// Output:
+	// 3
+	// -1
+}
+
+func ExampleLastIndexFunc() {
+	seq := iterutil.SeqOf(3, 5, 8, 1, 42, 7)
+	isEven := func(i int) bool { return i%2 == 0 }
+	fmt.Println(iterutil.LastIndexFunc(seq, isEven))
+	// Output:
+	// 4
+}
+
+func TestIndex(t *testing.T) {
+	cases := []struct {
+		desc          string
+		elems         []int
+		target        int
+		wantIndex     int
+		wantLastIndex int
+	}{
+		{
+			desc:          "empty",
+			target:        1,
+			wantIndex:     -1,
+			wantLastIndex: -1,
+		}, {
+			desc:          "absent",
+			elems:         []int{2, 3},
+			target:        1,
+			wantIndex:     -1,
+			wantLastIndex: -1,
+		}, {
+			desc:          "single occurrence",
+			elems:         []int{2, 1, 3},
+			target:        1,
+			wantIndex:     1,
+			wantLastIndex: 1,
+		}, {
+			desc:          "several occurrences",
+			elems:         []int{1, 2, 1, 3, 1, 4},
+			target:        1,
+			wantIndex:     0,
+			wantLastIndex: 4,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			if got := iterutil.Index(seq, tc.target); got != tc.wantIndex {
+				t.Errorf("Index: got %d; want %d", got, tc.wantIndex)
+			}
+			if got := iterutil.LastIndex(seq, tc.target); got != tc.wantLastIndex {
+				t.Errorf("LastIndex: got %d; want %d", got, tc.wantLastIndex)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+	// Index stops at the first occurrence, even in an infinite iterator.
+	naturals := iterutil.Iterate(0, func(i int) int { return i + 1 })
+	if got := iterutil.Index(naturals, 42); got != 42 {
+		t.Errorf("Index: got %d; want 42", got)
+	}
+}
+
+func ExampleFind2() {
+	seq := slices.All([]string{"foo", "bar", "baz"})
+	p := func(i int, s string) bool { return i > 0 && strings.HasPrefix(s, "b") }
+	fmt.Println(iterutil.Find2(seq, p))
+	fmt.Println(iterutil.Find2(seq, func(int, string) bool { return false }))
+	// Output:
+	// 1 bar true
+	// 0  false
+}
+
+func ExampleLookup() {
+	headers := iterutil.Zip(
+		iterutil.SeqOf("Host", "Accept", "Accept"),
+		iterutil.SeqOf("example.com", "text/html", "application/json"),
+	)
+	v, ok := iterutil.Lookup(headers, "Accept")
+	fmt.Printf("%q %t\n", v, ok)
+	v, ok = iterutil.Lookup(headers, "Origin")
+	fmt.Printf("%q %t\n", v, ok)
+	// Output:
+	// "text/html" true
+	// "" false
+}
+
+func ExampleAll() {
+	isEven := func(i int) bool { return i%2 == 0 }
+	fmt.Println(iterutil.All(iterutil.Empty[int](), isEven))
+	fmt.Println(iterutil.All(iterutil.SeqOf(2, 4, 6), isEven))
+	fmt.Println(iterutil.All(iterutil.SeqOf(2, 3, 6), isEven))
+	// Output:
+	// true
+	// true
+	// false
+}
+
+func ExampleNone() {
+	isEven := func(i int) bool { return i%2 == 0 }
+	fmt.Println(iterutil.None(iterutil.Empty[int](), isEven))
+	fmt.Println(iterutil.None(iterutil.SeqOf(1, 3, 5), isEven))
+	fmt.Println(iterutil.None(iterutil.SeqOf(1, 2, 5), isEven))
+	// Output:
+	// true
+	// true
+	// false
+}
+
+func ExampleCountFunc() {
+	isEven := func(i int) bool { return i%2 == 0 }
+	fmt.Println(iterutil.CountFunc(iterutil.SeqOf(1, 2, 3, 4, 6), isEven))
+	// Output:
+	// 3
+}
+
+func ExampleExactlyOne() {
+	fmt.Println(iterutil.ExactlyOne(iterutil.Empty[int]()))
+	fmt.Println(iterutil.ExactlyOne(iterutil.SeqOf(42)))
+	fmt.Println(iterutil.ExactlyOne(iterutil.SeqOf(1, 2)))
+	// Output:
+	// 0 iterutil: no elements
+	// 42 <nil>
+	// 0 iterutil: more than one element
+}
+
+// counting returns an iterator over the naturals that records, in *n,
+// the number of elements it has yielded.
+func counting(n *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for *n = 0; ; {
+			*n++
+			if !yield(*n - 1) {
+				return
+			}
+		}
+	}
+}
+
+func TestShortCircuitingSinks(t *testing.T) {
+	var n int
+	cases := []struct {
+		desc string
+		run  func() any
+		want any
+		// number of elements consumed
+		wantN int
+	}{
+		{
+			desc:  "All",
+			run:   func() any { return iterutil.All(counting(&n), func(i int) bool { return i < 3 }) },
+			want:  false,
+			wantN: 4,
+		}, {
+			desc:  "None",
+			run:   func() any { return iterutil.None(counting(&n), func(i int) bool { return i == 3 }) },
+			want:  false,
+			wantN: 4,
+		}, {
+			desc: "ExactlyOne",
+			run: func() any {
+				_, err := iterutil.ExactlyOne(counting(&n))
+				return err
+			},
+			want:  iterutil.ErrMultipleElements,
+			wantN: 2,
+		}, {
+			desc:  "AtMost",
+			run:   func() any { return iterutil.AtMost(counting(&n), 3) },
+			want:  false,
+			wantN: 4,
+		}, {
+			desc:  "AtMost zero",
+			run:   func() any { return iterutil.AtMost(counting(&n), 0) },
+			want:  false,
+			wantN: 1,
+		}, {
+			desc:  "AtLeast",
+			run:   func() any { return iterutil.AtLeast(counting(&n), 3) },
+			want:  true,
+			wantN: 3,
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			if got := tc.run(); got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+			if n != tc.wantN {
+				t.Errorf("consumed %d elements; want %d", n, tc.wantN)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleAtMost() {
+	seq := iterutil.SeqOf(1, 2, 3)
+	fmt.Println(iterutil.AtMost(seq, 2))
+	fmt.Println(iterutil.AtMost(seq, 3))
+	// Output:
+	// false
+	// true
+}
+
+func ExampleAtLeast() {
+	seq := iterutil.SeqOf(1, 2, 3)
+	fmt.Println(iterutil.AtLeast(seq, 3))
+	fmt.Println(iterutil.AtLeast(seq, 4))
+	// Output:
+	// true
+	// false
+}
+
+func TestAtMostAtLeast(t *testing.T) {
+	cases := []struct {
+		desc        string
+		len         int
+		n           int
+		wantAtMost  bool
+		wantAtLeast bool
+	}{
+		{desc: "empty, negative n", len: 0, n: -1, wantAtMost: false, wantAtLeast: true},
+		{desc: "empty, zero n", len: 0, n: 0, wantAtMost: true, wantAtLeast: true},
+		{desc: "empty, positive n", len: 0, n: 1, wantAtMost: true, wantAtLeast: false},
+		{desc: "shorter than n", len: 2, n: 3, wantAtMost: true, wantAtLeast: false},
+		{desc: "as long as n", len: 3, n: 3, wantAtMost: true, wantAtLeast: true},
+		{desc: "longer than n", len: 4, n: 3, wantAtMost: false, wantAtLeast: true},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := iterutil.Repeat(0, tc.len)
+			if got := iterutil.AtMost(seq, tc.n); got != tc.wantAtMost {
+				t.Errorf("AtMost: got %t; want %t", got, tc.wantAtMost)
+			}
+			if got := iterutil.AtLeast(seq, tc.n); got != tc.wantAtLeast {
+				t.Errorf("AtLeast: got %t; want %t", got, tc.wantAtLeast)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleHasPrefix() {
+	seq := iterutil.SeqOf(1, 2, 3, 4)
+	fmt.Println(iterutil.HasPrefix(seq, iterutil.SeqOf(1, 2)))
+	fmt.Println(iterutil.HasPrefix(seq, iterutil.SeqOf(2, 3)))
+	// HasPrefix terminates even on infinite iterators.
+	naturals := iterutil.Iterate(0, func(i int) int { return i + 1 })
+	fmt.Println(iterutil.HasPrefix(naturals, iterutil.SeqOf(0, 1, 2)))
+	// Output:
+	// true
+	// false
+	// true
+}
+
+func ExampleHasPrefixFunc() {
+	seq := iterutil.SeqOf("Foo", "BAR", "baz")
+	prefix := iterutil.SeqOf("foo", "bar")
+	fmt.Println(iterutil.HasPrefixFunc(seq, prefix, strings.EqualFold))
+	// Output:
+	// true
+}
+
+func ExampleHasSuffix() {
+	seq := iterutil.SeqOf(1, 2, 3, 4)
+	fmt.Println(iterutil.HasSuffix(seq, iterutil.SeqOf(3, 4)))
+	fmt.Println(iterutil.HasSuffix(seq, iterutil.SeqOf(2, 3)))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleHasSuffixFunc() {
+	seq := iterutil.SeqOf("Foo", "BAR", "baz")
+	suffix := iterutil.SeqOf("bar", "BAZ")
+	fmt.Println(iterutil.HasSuffixFunc(seq, suffix, strings.EqualFold))
+	// Output:
+	// true
+}
+
+func ExampleIndexSeq() {
+	seq := iterutil.SeqOf(1, 2, 1, 2, 3, 1)
+	fmt.Println(iterutil.IndexSeq(seq, iterutil.SeqOf(1, 2, 3)))
+	fmt.Println(iterutil.IndexSeq(seq, iterutil.SeqOf(3, 2)))
+	// Output:
+	// 2
+	// -1
+}
+
+func ExampleIndexSeqFunc() {
+	seq := iterutil.SeqOf("foo", "Bar", "BAZ", "qux")
+	fmt.Println(iterutil.IndexSeqFunc(seq, iterutil.SeqOf("bar", "baz"), strings.EqualFold))
+	// Output:
+	// 1
+}
+
+func ExampleContainsSubsequence() {
+	seq := iterutil.SeqOf(1, 2, 1, 2, 3, 1)
+	fmt.Println(iterutil.ContainsSubsequence(seq, iterutil.SeqOf(2, 3, 1)))
+	fmt.Println(iterutil.ContainsSubsequence(seq, iterutil.SeqOf(1, 3)))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleContainsSubsequenceFunc() {
+	seq := iterutil.SeqOf("foo", "Bar", "BAZ", "qux")
+	sub := iterutil.SeqOf("BAR", "baz")
+	fmt.Println(iterutil.ContainsSubsequenceFunc(seq, sub, strings.EqualFold))
+	// Output:
+	// true
+}
+
+func FuzzSubsequenceSearch(f *testing.F) {
+	f.Add([]byte(nil), []byte(nil))
+	f.Add([]byte("foo"), []byte(nil))
+	f.Add([]byte("foo"), []byte("foobar"))
+	f.Add([]byte("aaabaab"), []byte("aab"))
+	f.Add([]byte("xabaabaaby"), []byte("abaab"))
+	f.Fuzz(func(t *testing.T, s, sub []byte) {
+		seq, subSeq := slices.Values(s), slices.Values(sub)
+		if got, want := iterutil.HasPrefix(seq, subSeq), bytes.HasPrefix(s, sub); got != want {
+			t.Errorf("HasPrefix(%q, %q): got %t; want %t", s, sub, got, want)
+		}
+		if got, want := iterutil.HasSuffix(seq, subSeq), bytes.HasSuffix(s, sub); got != want {
+			t.Errorf("HasSuffix(%q, %q): got %t; want %t", s, sub, got, want)
+		}
+		if got, want := iterutil.IndexSeq(seq, subSeq), bytes.Index(s, sub); got != want {
+			t.Errorf("IndexSeq(%q, %q): got %d; want %d", s, sub, got, want)
+		}
+	})
+}
+
+func ExampleLastN() {
+	seq := slices.Values([]string{"foo", "bar", "baz", "qux"})
+	fmt.Println(iterutil.LastN(seq, 3))
+	fmt.Println(iterutil.LastN(seq, 10))
+	// Output:
+	// [bar baz qux]
+	// [foo bar baz qux]
+}
+
+func TestLastN(t *testing.T) {
+	for n := -1; n <= 12; n++ {
+		elems := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+		got := iterutil.LastN(slices.Values(elems), int8(n))
+		want := elems[len(elems)-min(max(n, 0), len(elems)):]
+		if !slices.Equal(got, want) {
+			t.Errorf("LastN(seq, %d): got %v; want %v", n, got, want)
+		}
+	}
+}
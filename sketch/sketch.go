@@ -0,0 +1,248 @@
+// Package sketch provides sinks and combinators that process
+// very large iterators in bounded memory at the cost of approximate results.
+//
+// Because the elements of iterators may be of any type,
+// all functions in this package require a hash function,
+// which must map equal elements to equal hashes
+// and should spread distinct elements uniformly over all 64 bits;
+// for strings, see [hash/maphash.String].
+package sketch
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+)
+
+// CountDistinctApprox returns an estimate of the number of distinct elements
+// in seq, as determined by hash, computed by the [HyperLogLog] algorithm
+// with 2^precision registers of one byte each.
+// The relative standard error of the estimate is approximately
+// 1.04/sqrt(2^precision); for instance, about 0.8% for a precision of 14,
+// which requires 16 KiB.
+// CountDistinctApprox panics if precision lies outside the range [4, 18].
+// CountDistinctApprox terminates if and only if seq is finite.
+//
+// [HyperLogLog]: https://en.wikipedia.org/wiki/HyperLogLog
+func CountDistinctApprox[E any](seq iter.Seq[E], precision int, hash func(E) uint64) uint64 {
+	if precision < 4 || 18 < precision {
+		panic("precision must be in the range [4, 18]")
+	}
+	p := uint(precision)
+	m := 1 << p
+	registers := make([]uint8, m)
+	for e := range seq {
+		h := hash(e)
+		i := h >> (64 - p)
+		// The sentinel bit caps the rank at 64-p+1.
+		w := h<<p | 1<<(p-1)
+		if rank := uint8(bits.LeadingZeros64(w) + 1); rank > registers[i] {
+			registers[i] = rank
+		}
+	}
+	var (
+		sum   float64
+		zeros int
+	)
+	for _, r := range registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	mf := float64(m)
+	estimate := alpha(m) * mf * mf / sum
+	if estimate <= 2.5*mf && zeros > 0 { // small-range correction
+		estimate = mf * math.Log(mf/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+// alpha returns the bias-correction constant for m registers.
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// BloomDistinct returns an iterator composed of the elements of seq
+// that, as determined by hash, have probably not been encountered earlier
+// in seq; membership is tracked by a [Bloom filter] sized for n distinct
+// elements and a false-positive rate of fpRate.
+// Because of false positives, some elements encountered for the first time
+// may be dropped, with a probability that remains below about fpRate
+// as long as seq contains no more than n distinct elements;
+// however, no duplicate is ever yielded.
+// Unlike exact deduplication, which must remember every distinct element,
+// BloomDistinct uses a fixed amount of memory,
+// about -n*ln(fpRate)/ln(2)^2 bits (e.g. about 1.2 MB for
+// one million elements and a false-positive rate of 0.01),
+// allocated anew for each iteration.
+// BloomDistinct panics if n is not positive or if fpRate lies outside
+// the range (0, 1).
+//
+// [Bloom filter]: https://en.wikipedia.org/wiki/Bloom_filter
+func BloomDistinct[E any](seq iter.Seq[E], n int, fpRate float64, hash func(E) uint64) iter.Seq[E] {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	if !(0 < fpRate && fpRate < 1) {
+		panic("fpRate must be in the range (0, 1)")
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := max(int(math.Round(float64(m)/float64(n)*math.Ln2)), 1)
+	return func(yield func(E) bool) {
+		filter := make([]uint64, (m+63)/64)
+		for e := range seq {
+			h1, h2 := split(hash(e))
+			seen := true
+			for i := range uint64(k) {
+				bit := (h1 + i*h2) % m
+				word, mask := bit/64, uint64(1)<<(bit%64)
+				if filter[word]&mask == 0 {
+					seen = false
+					filter[word] |= mask
+				}
+			}
+			if !seen && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// split derives, from h, two hashes suitable for [double hashing].
+//
+// [double hashing]: https://doi.org/10.1002/rsa.20208
+func split(h uint64) (uint64, uint64) {
+	return h, bits.RotateLeft64(h, 32)*0x9e3779b97f4a7c15 | 1
+}
+
+// A CountMinSketch estimates the number of occurrences of elements
+// in a stream by means of a [count-min sketch].
+// Estimates never underestimate the actual counts and,
+// with probability at least 1-delta, overestimate them by at most eps*N,
+// where N is the total number of occurrences added to the sketch.
+// A CountMinSketch must be created with [NewCountMinSketch].
+//
+// [count-min sketch]: https://en.wikipedia.org/wiki/Count%E2%80%93min_sketch
+type CountMinSketch[E any] struct {
+	width  uint64
+	counts [][]uint64 // one row per hash
+	total  uint64
+	hash   func(E) uint64
+}
+
+// NewCountMinSketch returns an empty count-min sketch
+// whose estimates are accurate up to eps*N with probability 1-delta.
+// The sketch uses about e/eps * ln(1/delta) counters of 8 bytes each;
+// for instance, about 150 KiB for an eps of 0.001 and a delta of 0.001.
+// NewCountMinSketch panics if eps or delta lies outside the range (0, 1).
+func NewCountMinSketch[E any](eps, delta float64, hash func(E) uint64) *CountMinSketch[E] {
+	if !(0 < eps && eps < 1) {
+		panic("eps must be in the range (0, 1)")
+	}
+	if !(0 < delta && delta < 1) {
+		panic("delta must be in the range (0, 1)")
+	}
+	width := uint64(math.Ceil(math.E / eps))
+	depth := max(int(math.Ceil(math.Log(1/delta))), 1)
+	counts := make([][]uint64, depth)
+	for i := range counts {
+		counts[i] = make([]uint64, width)
+	}
+	return &CountMinSketch[E]{
+		width:  width,
+		counts: counts,
+		hash:   hash,
+	}
+}
+
+// Add records count occurrences of e in s.
+func (s *CountMinSketch[E]) Add(e E, count uint64) {
+	h1, h2 := split(s.hash(e))
+	for i, row := range s.counts {
+		row[(h1+uint64(i)*h2)%s.width] += count
+	}
+	s.total += count
+}
+
+// AddAll records one occurrence of each element of seq in s.
+// It terminates if and only if seq is finite.
+func (s *CountMinSketch[E]) AddAll(seq iter.Seq[E]) {
+	for e := range seq {
+		s.Add(e, 1)
+	}
+}
+
+// Count returns an estimate of the number of occurrences of e in s.
+func (s *CountMinSketch[E]) Count(e E) uint64 {
+	h1, h2 := split(s.hash(e))
+	res := uint64(math.MaxUint64)
+	for i, row := range s.counts {
+		res = min(res, row[(h1+uint64(i)*h2)%s.width])
+	}
+	return res
+}
+
+// Total returns the total number of occurrences recorded in s.
+func (s *CountMinSketch[E]) Total() uint64 {
+	return s.total
+}
+
+// HeavyHitters returns the elements of seq whose number of occurrences
+// seemingly accounts for at least a fraction phi of the length of seq,
+// along with estimates of those numbers, in no particular order.
+// Occurrences are counted by a [CountMinSketch] of parameters eps and delta,
+// which should be much smaller than phi;
+// consequently, with probability 1-delta, the result contains all the
+// actual heavy hitters and no element that accounts for less than
+// a fraction phi-eps of the length of seq.
+// Besides the sketch, HeavyHitters only keeps track of the elements that
+// are heavy hitters of the part of seq consumed so far.
+// HeavyHitters panics if phi lies outside the range (0, 1] or
+// if eps or delta lies outside the range (0, 1).
+// HeavyHitters terminates if and only if seq is finite.
+func HeavyHitters[E comparable](
+	seq iter.Seq[E],
+	phi, eps, delta float64,
+	hash func(E) uint64,
+) map[E]uint64 {
+	if !(0 < phi && phi <= 1) {
+		panic("phi must be in the range (0, 1]")
+	}
+	s := NewCountMinSketch(eps, delta, hash)
+	candidates := make(map[E]uint64)
+	limit := 2 * int(math.Ceil(1/phi))
+	for e := range seq {
+		s.Add(e, 1)
+		if c := s.Count(e); float64(c) >= phi*float64(s.total) {
+			candidates[e] = c
+		}
+		if len(candidates) > limit {
+			prune(candidates, s, phi)
+		}
+	}
+	prune(candidates, s, phi)
+	return candidates
+}
+
+// prune removes, from candidates, the elements that no longer are
+// heavy hitters according to s, and refreshes the others' counts.
+func prune[E comparable](candidates map[E]uint64, s *CountMinSketch[E], phi float64) {
+	for e := range candidates {
+		c := s.Count(e)
+		if float64(c) < phi*float64(s.total) {
+			delete(candidates, e)
+			continue
+		}
+		candidates[e] = c
+	}
+}
@@ -0,0 +1,217 @@
+package sketch_test
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/sketch"
+)
+
+// hashInt is the finalizer of the SplitMix64 generator.
+func hashInt(i int) uint64 {
+	z := uint64(i)
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// ints returns an iterator over n integers
+// that take min(n, distinct) different values.
+func ints(n, distinct int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i * 7919 % distinct) {
+				return
+			}
+		}
+	}
+}
+
+func ExampleCountDistinctApprox() {
+	seed := maphash.MakeSeed()
+	hash := func(s string) uint64 { return maphash.String(seed, s) }
+	words := iterutil.SeqOf("foo", "bar", "foo", "baz", "bar", "foo")
+	fmt.Println(sketch.CountDistinctApprox(words, 14, hash))
+	// Output:
+	// 3
+}
+
+func TestCountDistinctApprox(t *testing.T) {
+	cases := []struct {
+		desc      string
+		n         int
+		distinct  int
+		precision int
+	}{
+		{desc: "empty", n: 0, distinct: 1, precision: 14},
+		{desc: "small cardinality", n: 1_000, distinct: 100, precision: 14},
+		{desc: "medium cardinality", n: 100_000, distinct: 20_000, precision: 14},
+		{desc: "large cardinality", n: 1_000_000, distinct: 500_000, precision: 14},
+		{desc: "low precision", n: 100_000, distinct: 50_000, precision: 8},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := sketch.CountDistinctApprox(ints(tc.n, tc.distinct), tc.precision, hashInt)
+			want := float64(min(tc.n, tc.distinct))
+			// allow four standard errors
+			tolerance := 4 * 1.04 / math.Sqrt(float64(int(1)<<tc.precision)) * want
+			if math.Abs(float64(got)-want) > tolerance {
+				t.Errorf("got %d; want %v ± %v", got, want, tolerance)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestCountDistinctApproxPanic(t *testing.T) {
+	for _, precision := range []int{3, 19} {
+		f := func(t *testing.T) {
+			defer func() {
+				const want = "precision must be in the range [4, 18]"
+				if r := recover(); r != want {
+					t.Errorf("got panic %v; want %v", r, want)
+				}
+			}()
+			sketch.CountDistinctApprox(iterutil.SeqOf(1), precision, hashInt)
+		}
+		t.Run(fmt.Sprint(precision), f)
+	}
+}
+
+func ExampleBloomDistinct() {
+	seq := iterutil.SeqOf(1, 2, 1, 3, 2, 4, 1)
+	for i := range sketch.BloomDistinct(seq, 100, 0.01, hashInt) {
+		fmt.Println(i)
+	}
+	// Output:
+	// 1
+	// 2
+	// 3
+	// 4
+}
+
+func TestBloomDistinct(t *testing.T) {
+	const (
+		distinct = 10_000
+		fpRate   = 0.01
+	)
+	seq := ints(3*distinct, distinct)
+	deduped := sketch.BloomDistinct(seq, distinct, fpRate, hashInt)
+	seen := make(map[int]bool)
+	for i := range iterutil.Checked(deduped) {
+		if seen[i] {
+			t.Fatalf("duplicate %d", i)
+		}
+		seen[i] = true
+	}
+	if dropped := distinct - len(seen); dropped > 2*fpRate*distinct {
+		t.Errorf("got %d dropped elements; want at most %v", dropped, 2*fpRate*distinct)
+	}
+	// The filter must be reset for each iteration.
+	if got := iterutil.Len(deduped); got != len(seen) {
+		t.Errorf("got %d elements on second iteration; want %d", got, len(seen))
+	}
+	// early break
+	got := slices.Collect(iterutil.Take(deduped, 3))
+	if want := []int{0, 7919, 5838}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func ExampleCountMinSketch() {
+	s := sketch.NewCountMinSketch(0.001, 0.001, hashInt)
+	s.AddAll(iterutil.SeqOf(1, 2, 1, 3, 1))
+	s.Add(2, 10)
+	fmt.Println(s.Count(1), s.Count(2), s.Count(3), s.Count(4), s.Total())
+	// Output:
+	// 3 11 1 0 15
+}
+
+func TestCountMinSketch(t *testing.T) {
+	const (
+		eps   = 0.001
+		delta = 0.001
+		n     = 100_000
+	)
+	s := sketch.NewCountMinSketch(eps, delta, hashInt)
+	want := make(map[int]uint64)
+	// a skewed distribution: i occurs about n/(i+1) times
+	for i := range 1_000 {
+		c := uint64(n / (i + 1))
+		s.Add(i, c)
+		want[i] = c
+	}
+	slack := uint64(eps * float64(s.Total()))
+	for i, c := range want {
+		got := s.Count(i)
+		if got < c || got > c+slack {
+			t.Errorf("Count(%d): got %d; want in [%d, %d]", i, got, c, c+slack)
+		}
+	}
+}
+
+func ExampleHeavyHitters() {
+	// 0 accounts for half of the elements, 1 for a quarter,
+	// and the remaining quarter is spread over many elements.
+	seq := func(yield func(int) bool) {
+		for i := range 10_000 {
+			var e int
+			switch i % 4 {
+			case 0, 2:
+				e = 0
+			case 1:
+				e = 1
+			default:
+				e = i
+			}
+			if !yield(e) {
+				return
+			}
+		}
+	}
+	hh := sketch.HeavyHitters(seq, 0.2, 0.01, 0.01, hashInt)
+	for _, e := range slices.Sorted(maps.Keys(hh)) {
+		fmt.Println(e)
+	}
+	// Output:
+	// 0
+	// 1
+}
+
+func TestHeavyHitters(t *testing.T) {
+	// Element i (for i in [0, 5)) occurs 1000*(5-i) times,
+	// interleaved with 20000 distinct other elements.
+	var elems []int
+	for i := range 5 {
+		for range 1000 * (5 - i) {
+			elems = append(elems, i)
+		}
+	}
+	for i := range 20_000 {
+		elems = append(elems, 100+i)
+	}
+	// deterministic shuffle
+	for i := range elems {
+		j := int(hashInt(i) % uint64(i+1))
+		elems[i], elems[j] = elems[j], elems[i]
+	}
+	// Elements 0, 1, and 2 account for 14%, 11%, and 9% of the total;
+	// elements 3 and 4 for 6% and 3%.
+	hh := sketch.HeavyHitters(slices.Values(elems), 0.08, 0.001, 0.001, hashInt)
+	got := slices.Sorted(maps.Keys(hh))
+	if want := []int{0, 1, 2}; !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	for e, c := range hh {
+		want := uint64(1000 * (5 - e))
+		if c < want || c > want+uint64(0.001*float64(len(elems))) {
+			t.Errorf("count of %d: got %d; want about %d", e, c, want)
+		}
+	}
+}
@@ -3,7 +3,13 @@ package iterutil
 import (
 	"cmp"
 	"iter"
+	"math/rand/v2"
+	"regexp"
+	"regexp/syntax"
 	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/jub0bs/iterutil/internal"
 	"golang.org/x/exp/constraints"
@@ -34,13 +40,13 @@ func Between[I constraints.Signed](n, m, step I) iter.Seq[I] {
 		panic("step cannot be zero")
 	case 1: // ascending
 		return func(yield func(I) bool) {
-			for ; n < m && yield(n); n += step {
+			for i := n; i < m && yield(i); i += step {
 				// deliberately empty
 			}
 		}
 	case -1: // descending
 		return func(yield func(I) bool) {
-			for ; n > m && yield(n); n += step {
+			for i := n; i > m && yield(i); i += step {
 				// deliberately empty
 			}
 		}
@@ -71,8 +77,42 @@ func Repeat[I constraints.Integer, E any](e E, count I) iter.Seq[E] {
 // of f to e.
 func Iterate[E any](e E, f func(E) E) iter.Seq[E] {
 	return func(yield func(E) bool) {
-		for yield(e) {
-			e = f(e)
+		for v := e; yield(v); v = f(v) {
+			// deliberately empty
+		}
+	}
+}
+
+// Unfold returns an iterator generated from seed by f,
+// which, given the current state, returns the next element,
+// the next state, and whether an element was produced at all.
+// The resulting iterator ends as soon as f returns false.
+// Each iteration over the resulting iterator starts afresh from seed.
+func Unfold[S, E any](seed S, f func(S) (E, S, bool)) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for s := seed; ; {
+			e, next, ok := f(s)
+			if !ok || !yield(e) {
+				return
+			}
+			s = next
+		}
+	}
+}
+
+// Unfold2 returns an iterator generated from seed by f,
+// which, given the current state, returns the next pair,
+// the next state, and whether a pair was produced at all.
+// The resulting iterator ends as soon as f returns false.
+// Each iteration over the resulting iterator starts afresh from seed.
+func Unfold2[S, K, V any](seed S, f func(S) (K, V, S, bool)) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for s := seed; ; {
+			k, v, next, ok := f(s)
+			if !ok || !yield(k, v) {
+				return
+			}
+			s = next
 		}
 	}
 }
@@ -90,6 +130,209 @@ func Cycle[E any](seq iter.Seq[E]) iter.Seq[E] {
 	}
 }
 
+// Permutations returns an iterator over all permutations of the elements of s,
+// in lexicographic order of their indices in s.
+// Permutations does not modify s;
+// note, however, that the resulting iterator reuses the same slice
+// for each permutation that it yields;
+// use [slices.Clone] to retain a permutation beyond the current iteration.
+func Permutations[S ~[]E, E any](s S) iter.Seq[[]E] {
+	return func(yield func([]E) bool) {
+		n := len(s)
+		idx := make([]int, n)
+		for i := range idx {
+			idx[i] = i
+		}
+		buf := make([]E, n)
+		for {
+			for i, j := range idx {
+				buf[i] = s[j]
+			}
+			if !yield(buf) {
+				return
+			}
+			// See https://en.wikipedia.org/wiki/Permutation#Generation_in_lexicographic_order.
+			i := n - 2
+			for i >= 0 && idx[i] > idx[i+1] {
+				i--
+			}
+			if i < 0 { // last permutation
+				return
+			}
+			j := n - 1
+			for idx[j] < idx[i] {
+				j--
+			}
+			idx[i], idx[j] = idx[j], idx[i]
+			slices.Reverse(idx[i+1:])
+		}
+	}
+}
+
+// Combinations returns an iterator over all k-combinations
+// (without replacement) of the elements of s,
+// in lexicographic order of their indices in s.
+// The resulting iterator is empty if k is negative or greater than len(s).
+// Combinations does not modify s;
+// note, however, that the resulting iterator reuses the same slice
+// for each combination that it yields;
+// use [slices.Clone] to retain a combination beyond the current iteration.
+func Combinations[I constraints.Integer, S ~[]E, E any](s S, k I) iter.Seq[[]E] {
+	if k < 0 || uint64(len(s)) < uint64(k) {
+		return Empty[[]E]()
+	}
+	return func(yield func([]E) bool) {
+		n, k := len(s), int(k)
+		idx := make([]int, k)
+		for i := range idx {
+			idx[i] = i
+		}
+		buf := make([]E, k)
+		for {
+			for i, j := range idx {
+				buf[i] = s[j]
+			}
+			if !yield(buf) {
+				return
+			}
+			i := k - 1
+			for i >= 0 && idx[i] == i+n-k {
+				i--
+			}
+			if i < 0 { // last combination
+				return
+			}
+			idx[i]++
+			for j := i + 1; j < k; j++ {
+				idx[j] = idx[j-1] + 1
+			}
+		}
+	}
+}
+
+// CombinationsWithReplacement returns an iterator over all k-combinations
+// with replacement of the elements of s,
+// in lexicographic order of their indices in s.
+// The resulting iterator is empty if k is negative
+// or if s is empty and k is positive.
+// CombinationsWithReplacement does not modify s;
+// note, however, that the resulting iterator reuses the same slice
+// for each combination that it yields;
+// use [slices.Clone] to retain a combination beyond the current iteration.
+func CombinationsWithReplacement[I constraints.Integer, S ~[]E, E any](s S, k I) iter.Seq[[]E] {
+	if k < 0 || len(s) == 0 && k > 0 {
+		return Empty[[]E]()
+	}
+	return func(yield func([]E) bool) {
+		n, k := len(s), int(k)
+		idx := make([]int, k)
+		buf := make([]E, k)
+		for {
+			for i, j := range idx {
+				buf[i] = s[j]
+			}
+			if !yield(buf) {
+				return
+			}
+			i := k - 1
+			for i >= 0 && idx[i] == n-1 {
+				i--
+			}
+			if i < 0 { // last combination
+				return
+			}
+			v := idx[i] + 1
+			for j := i; j < k; j++ {
+				idx[j] = v
+			}
+		}
+	}
+}
+
+// Product returns an iterator over the [Cartesian product] of seqs,
+// in lexicographic order.
+// Because Product ranges over all of seqs but the first one several times,
+// those iterators must be re-iterable.
+// Note that the resulting iterator reuses the same slice
+// for each tuple that it yields;
+// use [slices.Clone] to retain a tuple beyond the current iteration.
+//
+// [Cartesian product]: https://en.wikipedia.org/wiki/Cartesian_product
+func Product[E any](seqs ...iter.Seq[E]) iter.Seq[[]E] {
+	return func(yield func([]E) bool) {
+		buf := make([]E, len(seqs))
+		product(seqs, buf, 0, yield)
+	}
+}
+
+// product fills buf[i:] with all the tuples of the Cartesian product
+// of seqs[i:] and passes buf to yield after each of them.
+// It reports whether iteration should continue.
+func product[E any](seqs []iter.Seq[E], buf []E, i int, yield func([]E) bool) bool {
+	if i == len(seqs) {
+		return yield(buf)
+	}
+	for e := range seqs[i] {
+		buf[i] = e
+		if !product(seqs, buf, i+1, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// PowerSet returns an iterator over all the subsets of the elements of s,
+// ordered first by size and then
+// in lexicographic order of their indices in s.
+// PowerSet does not modify s;
+// note, however, that the resulting iterator reuses slices
+// across the subsets that it yields;
+// use [slices.Clone] to retain a subset beyond the current iteration.
+func PowerSet[S ~[]E, E any](s S) iter.Seq[[]E] {
+	return func(yield func([]E) bool) {
+		for k := range len(s) + 1 {
+			for c := range Combinations(s, k) {
+				if !yield(c) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Shuffled returns an iterator over the elements of s
+// in a uniformly random order, using rng as source of randomness.
+// Shuffled does not modify s; moreover,
+// the resulting iterator only shuffles as many elements as get consumed,
+// in time and space proportional to the number of consumed elements.
+// Each iteration over the resulting iterator draws anew from rng.
+func Shuffled[S ~[]E, E any](s S, rng *rand.Rand) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		// We perform a Fisher-Yates shuffle of a virtual copy of s;
+		// displaced records the elements of the copy that differ from
+		// those of s.
+		// See https://en.wikipedia.org/wiki/Fisher%E2%80%93Yates_shuffle.
+		displaced := make(map[int]E)
+		at := func(i int) E {
+			if e, found := displaced[i]; found {
+				return e
+			}
+			return s[i]
+		}
+		for i := range len(s) {
+			j := i + rng.IntN(len(s)-i)
+			e := at(j)
+			if j != i {
+				displaced[j] = at(i)
+			}
+			delete(displaced, i) // no longer needed
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
 // SortedFromMap returns an iterator over the key-value pairs in m
 // ordered by its keys.
 func SortedFromMap[M ~map[K]V, K cmp.Ordered, V any](m M) iter.Seq2[K, V] {
@@ -174,3 +417,348 @@ func keys[K comparable, V any](m map[K]V) []K {
 	}
 	return ks
 }
+
+// SplitString returns an iterator over the substrings of s
+// separated by sep, as would be returned by [strings.Split].
+// Contrary to [strings.Split], SplitString does not allocate.
+func SplitString(s, sep string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if sep == "" {
+			// Split after each UTF-8 sequence,
+			// each of which is either a valid rune or an invalid byte.
+			for rest := s; rest != ""; {
+				_, size := utf8.DecodeRuneInString(rest)
+				if !yield(rest[:size]) {
+					return
+				}
+				rest = rest[size:]
+			}
+			return
+		}
+		rest := s
+		for {
+			i := strings.Index(rest, sep)
+			if i < 0 {
+				yield(rest)
+				return
+			}
+			if !yield(rest[:i]) {
+				return
+			}
+			rest = rest[i+len(sep):]
+		}
+	}
+}
+
+// Fields returns an iterator over the substrings of s
+// separated by runs of white space (as defined by [unicode.IsSpace]),
+// as would be returned by [strings.Fields].
+// Contrary to [strings.Fields], Fields does not allocate.
+func Fields(s string) iter.Seq[string] {
+	return FieldsFunc(s, unicode.IsSpace)
+}
+
+// FieldsFunc returns an iterator over the substrings of s
+// separated by runs of runes that satisfy f,
+// as would be returned by [strings.FieldsFunc].
+// Contrary to [strings.FieldsFunc], FieldsFunc does not allocate.
+func FieldsFunc(s string, f func(rune) bool) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		start := -1 // start of the current field, if any
+		for i, r := range s {
+			switch {
+			case !f(r):
+				if start < 0 {
+					start = i
+				}
+			case start >= 0:
+				if !yield(s[start:i]) {
+					return
+				}
+				start = -1
+			}
+		}
+		if start >= 0 {
+			yield(s[start:])
+		}
+	}
+}
+
+// Runes returns an iterator over the runes of s
+// along with their byte offsets in s.
+// Each invalid byte in s is yielded as [utf8.RuneError].
+func Runes(s string) iter.Seq2[int, rune] {
+	return func(yield func(int, rune) bool) {
+		for i, r := range s {
+			if !yield(i, r) {
+				return
+			}
+		}
+	}
+}
+
+// RegexpMatches returns an iterator over the successive matches of re in s,
+// as would be returned by [regexp.Regexp.FindAllStringSubmatch]:
+// each match is a slice holding the text of the leftmost match
+// and of its subexpressions, if any.
+// Matches are searched for lazily,
+// i.e. only as far as the resulting iterator gets consumed.
+func RegexpMatches(re *regexp.Regexp, s string) iter.Seq[[]string] {
+	matchIndices := RegexpMatchIndices(re, s)
+	return func(yield func([]string) bool) {
+		for m := range matchIndices {
+			sub := make([]string, len(m)/2)
+			for i := range sub {
+				if m[2*i] >= 0 {
+					sub[i] = s[m[2*i]:m[2*i+1]]
+				}
+			}
+			if !yield(sub) {
+				return
+			}
+		}
+	}
+}
+
+// RegexpMatchIndices returns an iterator over the index pairs identifying
+// the successive matches of re in s,
+// as would be returned by [regexp.Regexp.FindAllStringSubmatchIndex]:
+// each match is a slice holding the index pairs of the leftmost match
+// and of its subexpressions, if any.
+// Matches are searched for lazily,
+// i.e. only as far as the resulting iterator gets consumed,
+// unless re contains empty-width assertions (such as ^ or \b)
+// that depend on the text preceding a match.
+func RegexpMatchIndices(re *regexp.Regexp, s string) iter.Seq[[]int] {
+	if !isContextFree(re) {
+		// Searching s[pos:] rather than s could cause spurious matches;
+		// we have no choice but to find all matches upfront.
+		return func(yield func([]int) bool) {
+			for _, m := range re.FindAllStringSubmatchIndex(s, -1) {
+				if !yield(m) {
+					return
+				}
+			}
+		}
+	}
+	return func(yield func([]int) bool) {
+		// This loop mirrors the one in regexp.Regexp.allMatches.
+		for pos, prevMatchEnd := 0, -1; pos <= len(s); {
+			m := re.FindStringSubmatchIndex(s[pos:])
+			if m == nil {
+				return
+			}
+			for i := range m {
+				if m[i] >= 0 {
+					m[i] += pos
+				}
+			}
+			accept := true
+			if m[1] == pos { // empty match
+				// An empty match right after a previous match is ignored.
+				accept = m[0] != prevMatchEnd
+				if pos < len(s) {
+					_, size := utf8.DecodeRuneInString(s[pos:])
+					pos += size
+				} else {
+					pos++
+				}
+			} else {
+				pos = m[1]
+			}
+			prevMatchEnd = m[1]
+			if accept && !yield(m) {
+				return
+			}
+		}
+	}
+}
+
+// isContextFree reports whether the matches of re in a suffix of a string
+// are guaranteed to be matches of re in the whole string.
+func isContextFree(re *regexp.Regexp) bool {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return !hasLeftContextAssertion(parsed)
+}
+
+func hasLeftContextAssertion(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginLine,
+		syntax.OpBeginText,
+		syntax.OpWordBoundary,
+		syntax.OpNoWordBoundary:
+		return true
+	}
+	return slices.ContainsFunc(re.Sub, hasLeftContextAssertion)
+}
+
+// DFS returns an iterator over the nodes of the tree rooted at root,
+// along with their depths (0 for root),
+// in depth-first pre-order (each node before its children);
+// children returns the children of each node.
+// If maxDepth is non-negative, nodes deeper than maxDepth are neither yielded
+// nor explored; otherwise, the entire tree is explored.
+// Nodes are explored only as far as the resulting iterator gets consumed.
+// Because DFS does not keep track of visited nodes,
+// it may not terminate if the structure described by children
+// contains cycles; see [DFSGraph].
+func DFS[N any](root N, children func(N) iter.Seq[N], maxDepth int) iter.Seq2[N, int] {
+	return func(yield func(N, int) bool) {
+		preOrder(root, 0, children, maxDepth, yield)
+	}
+}
+
+// preOrder performs a depth-first pre-order traversal of the tree rooted at n
+// and reports whether iteration should continue.
+func preOrder[N any](
+	n N,
+	depth int,
+	children func(N) iter.Seq[N],
+	maxDepth int,
+	yield func(N, int) bool,
+) bool {
+	if !yield(n, depth) {
+		return false
+	}
+	if depth == maxDepth {
+		return true
+	}
+	for c := range children(n) {
+		if !preOrder(c, depth+1, children, maxDepth, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// PostOrder returns an iterator over the nodes of the tree rooted at root,
+// along with their depths (0 for root),
+// in depth-first post-order (each node after its children);
+// children returns the children of each node.
+// If maxDepth is non-negative, nodes deeper than maxDepth are neither yielded
+// nor explored; otherwise, the entire tree is explored.
+// Because PostOrder does not keep track of visited nodes,
+// it may not terminate if the structure described by children
+// contains cycles.
+func PostOrder[N any](root N, children func(N) iter.Seq[N], maxDepth int) iter.Seq2[N, int] {
+	return func(yield func(N, int) bool) {
+		postOrder(root, 0, children, maxDepth, yield)
+	}
+}
+
+// postOrder performs a depth-first post-order traversal of the tree rooted at
+// n and reports whether iteration should continue.
+func postOrder[N any](
+	n N,
+	depth int,
+	children func(N) iter.Seq[N],
+	maxDepth int,
+	yield func(N, int) bool,
+) bool {
+	if depth != maxDepth {
+		for c := range children(n) {
+			if !postOrder(c, depth+1, children, maxDepth, yield) {
+				return false
+			}
+		}
+	}
+	return yield(n, depth)
+}
+
+// BFS returns an iterator over the nodes of the tree rooted at root,
+// along with their depths (0 for root),
+// in breadth-first order (level by level);
+// children returns the children of each node.
+// If maxDepth is non-negative, nodes deeper than maxDepth are neither yielded
+// nor explored; otherwise, the entire tree is explored.
+// Nodes are explored only as far as the resulting iterator gets consumed.
+// Because BFS does not keep track of visited nodes,
+// it may not terminate if the structure described by children
+// contains cycles; see [BFSGraph].
+func BFS[N any](root N, children func(N) iter.Seq[N], maxDepth int) iter.Seq2[N, int] {
+	return func(yield func(N, int) bool) {
+		bfs(root, children, maxDepth, nil, yield)
+	}
+}
+
+// BFSGraph returns an iterator over the nodes of the graph reachable from root,
+// along with their distances from root (0 for root),
+// in breadth-first order; neighbors returns the neighbors of each node.
+// Each reachable node is yielded exactly once.
+// If maxDepth is non-negative, nodes farther than maxDepth from root
+// are neither yielded nor explored; otherwise, the entire graph is explored.
+// Nodes are explored only as far as the resulting iterator gets consumed.
+func BFSGraph[N comparable](root N, neighbors func(N) iter.Seq[N], maxDepth int) iter.Seq2[N, int] {
+	return func(yield func(N, int) bool) {
+		visited := map[N]struct{}{root: {}}
+		discover := func(n N) bool {
+			if _, found := visited[n]; found {
+				return false
+			}
+			visited[n] = struct{}{}
+			return true
+		}
+		bfs(root, neighbors, maxDepth, discover, yield)
+	}
+}
+
+// bfs performs a breadth-first traversal from root.
+// If discover is not nil, it's called on each node as it is discovered
+// and only the nodes for which it returns true get enqueued.
+func bfs[N any](
+	root N,
+	children func(N) iter.Seq[N],
+	maxDepth int,
+	discover func(N) bool,
+	yield func(N, int) bool,
+) {
+	type item struct {
+		node  N
+		depth int
+	}
+	queue := []item{{root, 0}}
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+		if !yield(it.node, it.depth) {
+			return
+		}
+		if it.depth == maxDepth {
+			continue
+		}
+		for c := range children(it.node) {
+			if discover != nil && !discover(c) {
+				continue
+			}
+			queue = append(queue, item{c, it.depth + 1})
+		}
+	}
+}
+
+// DFSGraph returns an iterator over the nodes of the graph reachable from root,
+// along with their depths in the depth-first search tree (0 for root),
+// in depth-first pre-order; neighbors returns the neighbors of each node.
+// Each reachable node is yielded exactly once.
+// If maxDepth is non-negative, nodes deeper than maxDepth in the
+// depth-first search tree are neither yielded nor explored;
+// otherwise, the entire graph is explored.
+// Nodes are explored only as far as the resulting iterator gets consumed.
+func DFSGraph[N comparable](root N, neighbors func(N) iter.Seq[N], maxDepth int) iter.Seq2[N, int] {
+	return func(yield func(N, int) bool) {
+		visited := make(map[N]struct{})
+		unvisited := func(n N) iter.Seq[N] {
+			return Filter(neighbors(n), func(m N) bool {
+				_, found := visited[m]
+				return !found
+			})
+		}
+		markVisited := func(n N, depth int) bool {
+			visited[n] = struct{}{}
+			return yield(n, depth)
+		}
+		preOrder(root, 0, unvisited, maxDepth, markVisited)
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"cmp"
 	"fmt"
 	"iter"
+	"math/rand/v2"
+	"regexp"
 	"slices"
 	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/iterutiltest"
 )
 
 func ExampleEmpty() {
@@ -18,6 +22,11 @@ func ExampleEmpty() {
 	// Output:
 }
 
+func TestEmpty(t *testing.T) {
+	got := iterutil.Empty[string]()
+	assertEqual(t, got, nil, alwaysFalse[string])
+}
+
 func ExampleSeqOf() {
 	for i := range iterutil.SeqOf(1, 2, 3) {
 		fmt.Println(i)
@@ -181,6 +190,7 @@ func TestBetween(t *testing.T) {
 			}
 			got := iterutil.Between(tc.n, tc.m, tc.step)
 			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
 		}
 		t.Run(tc.desc, f)
 	}
@@ -282,227 +292,658 @@ func ExampleIterate() {
 	// 16
 }
 
-func ExampleCycle() {
-	seq := slices.Values([]int{1, 2, 3})
-	var count int
-	for i := range iterutil.Cycle(seq) {
-		count++
-		if count > 5 {
-			break
+func TestIterate(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elem      int
+		f         func(int) int
+		breakWhen func(int) bool
+		want      []int
+	}{
+		{
+			desc:      "break immediately",
+			elem:      1,
+			f:         func(i int) int { return i + i },
+			breakWhen: equal(1),
+		}, {
+			desc:      "break later",
+			elem:      1,
+			f:         func(i int) int { return i + i },
+			breakWhen: equal(32),
+			want:      []int{1, 2, 4, 8, 16},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.Iterate(tc.elem, tc.f)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, iterutil.Take(got, 10))
 		}
-		fmt.Println(i)
+		t.Run(tc.desc, f)
 	}
-	// Output:
-	// 1
-	// 2
-	// 3
-	// 1
-	// 2
 }
 
-func ExampleSortedFromMap() {
-	m := map[string]int{
-		"one":   1,
-		"two":   2,
-		"three": 3,
+func ExampleUnfold() {
+	// a paginated API, simulated
+	pages := map[string]struct {
+		items []string
+		next  string
+	}{
+		"":   {items: []string{"foo", "bar"}, next: "p2"},
+		"p2": {items: []string{"baz"}, next: "p3"},
+		"p3": {items: []string{"qux"}},
 	}
-	for k, v := range iterutil.SortedFromMap(m) {
-		fmt.Println(k, v)
+	type cursor struct {
+		token string
+		done  bool
+	}
+	fetch := func(c cursor) ([]string, cursor, bool) {
+		if c.done {
+			return nil, c, false
+		}
+		page := pages[c.token]
+		return page.items, cursor{page.next, page.next == ""}, true
+	}
+	for items := range iterutil.Unfold(cursor{}, fetch) {
+		fmt.Println(items)
 	}
 	// Output:
-	// one 1
-	// three 3
-	// two 2
+	// [foo bar]
+	// [baz]
+	// [qux]
 }
 
-func TestSortedFromMap(t *testing.T) {
+func TestUnfold(t *testing.T) {
+	// countdown yields n, n-1, ..., 1
+	countdown := func(n int) (int, int, bool) {
+		return n, n - 1, n > 0
+	}
 	cases := []struct {
 		desc      string
-		m         map[string]int
-		breakWhen func(string, int) bool
-		want      []Pair[string, int]
+		seed      int
+		breakWhen func(int) bool
+		want      []int
 	}{
 		{
-			desc: "no break",
-			m: map[string]int{
-				"one":   1,
-				"two":   2,
-				"three": 3,
-			},
-			breakWhen: alwaysFalse2[string, int],
-			want: []Pair[string, int]{
-				{"one", 1},
-				{"three", 3},
-				{"two", 2},
-			},
+			desc:      "empty",
+			seed:      0,
+			breakWhen: alwaysFalse[int],
 		}, {
-			desc: "break early",
-			m: map[string]int{
-				"one":   1,
-				"two":   2,
-				"three": 3,
-			},
-			breakWhen: equal2("three", 3),
-			want: []Pair[string, int]{
-				{"one", 1},
-			},
+			desc:      "no break",
+			seed:      3,
+			breakWhen: alwaysFalse[int],
+			want:      []int{3, 2, 1},
+		}, {
+			desc:      "break early",
+			seed:      3,
+			breakWhen: equal(1),
+			want:      []int{3, 2},
 		},
 	}
 	for _, tc := range cases {
 		f := func(t *testing.T) {
-			got := iterutil.SortedFromMap(tc.m)
-			assertEqual2(t, got, tc.want, tc.breakWhen)
-			// sanity check: also test referenceSortedFromMap
-			got = referenceSortedFromMap(tc.m)
-			assertEqual2(t, got, tc.want, tc.breakWhen)
+			got := iterutil.Unfold(tc.seed, countdown)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, got)
 		}
 		t.Run(tc.desc, f)
 	}
 }
 
-func BenchmarkSortedFromMap(b *testing.B) {
-	type Case struct {
-		pairs    int
-		consumed string
-		breakAt  int
+func ExampleUnfold2() {
+	// fib yields the index and value of Fibonacci numbers less than 50.
+	fib := func(s [3]int) (int, int, [3]int, bool) {
+		i, a, b := s[0], s[1], s[2]
+		return i, a, [3]int{i + 1, b, a + b}, a < 50
 	}
-	var cases []Case
-	const maxExp = 12
-
-	for n := range iterutil.Between(4, 11, 1) {
-		bc := Case{
-			pairs:    1 << n,
-			consumed: "at most 16",
-			breakAt:  min(16, 1<<n),
-		}
-		cases = append(cases, bc)
-
-		bc = Case{
-			pairs:    1 << n,
-			consumed: "half",
-			breakAt:  1 << (n - 1),
-		}
-		cases = append(cases, bc)
-
-		bc = Case{
-			pairs:    1 << n,
-			consumed: "all",
-			breakAt:  1 << n,
-		}
-		cases = append(cases, bc)
+	for i, n := range iterutil.Unfold2([3]int{0, 0, 1}, fib) {
+		fmt.Println(i, n)
 	}
-	plusOne := func(i int) int { return i + 1 }
-	for _, bc := range cases {
-		seq := iterutil.Take(iterutil.Iterate(0, plusOne), bc.pairs)
-		m := make(map[int]struct{}, bc.pairs)
-		for k := range seq {
-			m[k] = struct{}{}
+	// Output:
+	// 0 0
+	// 1 1
+	// 2 1
+	// 3 2
+	// 4 3
+	// 5 5
+	// 6 8
+	// 7 13
+	// 8 21
+	// 9 34
+}
+
+func TestUnfold2(t *testing.T) {
+	// tokens splits a string into its whitespace-separated fields,
+	// along with their byte offsets.
+	tokens := func(s string) iter.Seq2[int, string] {
+		type state struct {
+			offset int
+			rest   string
 		}
-		f := func(b *testing.B) {
-			b.ReportAllocs()
-			for range b.N {
-				for k := range iterutil.SortedFromMap(m) {
-					if k == bc.breakAt {
-						break
-					}
-				}
+		f := func(st state) (int, string, state, bool) {
+			trimmed := strings.TrimLeft(st.rest, " ")
+			offset := st.offset + len(st.rest) - len(trimmed)
+			if trimmed == "" {
+				return 0, "", st, false
 			}
-		}
-		const tmpl = "impl=%s/pairs=%d/consumed=%s"
-		name := fmt.Sprintf(tmpl, "binary_heap", bc.pairs, bc.consumed)
-		b.Run(name, f)
-		f = func(b *testing.B) {
-			b.ReportAllocs()
-			for range b.N {
-				for k := range referenceSortedFromMap(m) {
-					if k == bc.breakAt {
-						break
-					}
-				}
+			tok, rest, found := strings.Cut(trimmed, " ")
+			next := state{offset + len(tok), rest}
+			if found {
+				next.offset++
 			}
+			return offset, tok, next, true
 		}
-		name = fmt.Sprintf(tmpl, "upfront_sort", bc.pairs, bc.consumed)
-		b.Run(name, f)
+		return iterutil.Unfold2(state{0, s}, f)
+	}
+	cases := []struct {
+		desc      string
+		s         string
+		breakWhen func(int, string) bool
+		want      []Pair[int, string]
+	}{
+		{
+			desc:      "empty",
+			s:         "  ",
+			breakWhen: alwaysFalse2[int, string],
+		}, {
+			desc:      "no break",
+			s:         " foo  bar baz",
+			breakWhen: alwaysFalse2[int, string],
+			want: []Pair[int, string]{
+				{1, "foo"},
+				{6, "bar"},
+				{10, "baz"},
+			},
+		}, {
+			desc:      "break early",
+			s:         " foo  bar baz",
+			breakWhen: equal2(10, "baz"),
+			want: []Pair[int, string]{
+				{1, "foo"},
+				{6, "bar"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := tokens(tc.s)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, iterutil.Left(got))
+		}
+		t.Run(tc.desc, f)
 	}
 }
 
-func referenceSortedFromMap[M ~map[K]V, K cmp.Ordered, V any](m M) iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		ks := make([]K, 0, len(m))
-		for k := range m {
-			ks = append(ks, k)
-		}
-		slices.Sort(ks)
-		for _, k := range ks {
-			if !yield(k, m[k]) {
-				return
-			}
+func ExampleCycle() {
+	seq := slices.Values([]int{1, 2, 3})
+	var count int
+	for i := range iterutil.Cycle(seq) {
+		count++
+		if count > 5 {
+			break
 		}
+		fmt.Println(i)
 	}
+	// Output:
+	// 1
+	// 2
+	// 3
+	// 1
+	// 2
 }
 
-func ExampleSortedFromMapFunc() {
-	m := map[string]int{
-		"one":   1,
-		"two":   2,
-		"three": 3,
+func TestCycle(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "break within first cycle",
+			elems:     []string{"one", "two", "three"},
+			breakWhen: equal("three"),
+			want:      []string{"one", "two"},
+		}, {
+			desc:  "break within second cycle",
+			elems: []string{"one", "two", "three"},
+			breakWhen: func() func(string) bool {
+				var n int
+				return func(string) bool {
+					n++
+					return n > 5
+				}
+			}(),
+			want: []string{"one", "two", "three", "one", "two"},
+		},
 	}
-	for k, v := range iterutil.SortedFromMapFunc(m, strings.Compare) {
-		fmt.Println(k, v)
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := slices.Values(tc.elems)
+			got := iterutil.Cycle(seq)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
 	}
-	// Output:
-	// one 1
-	// three 3
-	// two 2
 }
 
-func ExampleSortedFromMapFunc_incorrect() {
-	m := map[string]int{
-		"one":   1,
-		"two":   2,
-		"three": 3,
-	}
-	lenCmp := func(s1, s2 string) int { return cmp.Compare(len(s1), len(s2)) }
-	// Note that lenCmp does not correspond to a total order on strings.
-	// More specifically, lenCmp is not antisymmetric:
-	// for example, lenCmp("one", "two") = 0 and lenCmp("two", "one") = 0,
-	// but "one" != "two".
-	for k, v := range iterutil.SortedFromMapFunc(m, lenCmp) {
-		fmt.Println(k, v)
+func ExamplePermutations() {
+	for p := range iterutil.Permutations([]int{1, 2, 3}) {
+		fmt.Println(p)
 	}
-	// Consequently, the output is nondeterministic; it may be either
-	//
-	// one 1
-	// two 2
-	// three 3
-	//
-	// or
-	//
-	// two 2
-	// one 1
-	// three 3
+	// Output:
+	// [1 2 3]
+	// [1 3 2]
+	// [2 1 3]
+	// [2 3 1]
+	// [3 1 2]
+	// [3 2 1]
 }
 
-func TestSortedFromMapFunc(t *testing.T) {
+func TestPermutations(t *testing.T) {
 	cases := []struct {
 		desc      string
-		m         map[string]int
-		breakWhen func(string, int) bool
-		want      []Pair[string, int]
+		elems     []string
+		breakWhen func(string) bool
+		want      []string
 	}{
 		{
-			desc: "no break",
-			m: map[string]int{
-				"one":   1,
-				"two":   2,
-				"three": 3,
-			},
-			breakWhen: alwaysFalse2[string, int],
-			want: []Pair[string, int]{
-				{"one", 1},
-				{"three", 3},
-				{"two", 2},
-			},
+			desc:      "empty",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[]"},
+		}, {
+			desc:      "one element",
+			elems:     []string{"a"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[a]"},
+		}, {
+			desc:      "no break",
+			elems:     []string{"a", "b", "c"},
+			breakWhen: alwaysFalse[string],
+			want: []string{
+				"[a b c]",
+				"[a c b]",
+				"[b a c]",
+				"[b c a]",
+				"[c a b]",
+				"[c b a]",
+			},
+		}, {
+			desc:      "duplicates are not special",
+			elems:     []string{"a", "a"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[a a]", "[a a]"},
+		}, {
+			desc:      "break early",
+			elems:     []string{"a", "b", "c"},
+			breakWhen: equal("[b c a]"),
+			want:      []string{"[a b c]", "[a c b]", "[b a c]"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := iterutil.Permutations(tc.elems)
+			got := iterutil.Map(seq, sprint)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleCombinations() {
+	for c := range iterutil.Combinations([]int{1, 2, 3, 4}, 2) {
+		fmt.Println(c)
+	}
+	// Output:
+	// [1 2]
+	// [1 3]
+	// [1 4]
+	// [2 3]
+	// [2 4]
+	// [3 4]
+}
+
+func TestCombinations(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []string
+		k         int
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "negative k",
+			elems:     []string{"a", "b"},
+			k:         -1,
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "zero k",
+			elems:     []string{"a", "b"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[]"},
+		}, {
+			desc:      "k greater than length",
+			elems:     []string{"a", "b"},
+			k:         3,
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "k equal to length",
+			elems:     []string{"a", "b"},
+			k:         2,
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[a b]"},
+		}, {
+			desc:      "no break",
+			elems:     []string{"a", "b", "c", "d"},
+			k:         3,
+			breakWhen: alwaysFalse[string],
+			want: []string{
+				"[a b c]",
+				"[a b d]",
+				"[a c d]",
+				"[b c d]",
+			},
+		}, {
+			desc:      "break early",
+			elems:     []string{"a", "b", "c", "d"},
+			k:         3,
+			breakWhen: equal("[a c d]"),
+			want:      []string{"[a b c]", "[a b d]"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := iterutil.Combinations(tc.elems, tc.k)
+			got := iterutil.Map(seq, sprint)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleCombinationsWithReplacement() {
+	for c := range iterutil.CombinationsWithReplacement([]int{1, 2, 3}, 2) {
+		fmt.Println(c)
+	}
+	// Output:
+	// [1 1]
+	// [1 2]
+	// [1 3]
+	// [2 2]
+	// [2 3]
+	// [3 3]
+}
+
+func TestCombinationsWithReplacement(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []string
+		k         int
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "negative k",
+			elems:     []string{"a", "b"},
+			k:         -1,
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "zero k",
+			elems:     []string{"a", "b"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[]"},
+		}, {
+			desc:      "empty slice",
+			k:         2,
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "no break",
+			elems:     []string{"a", "b"},
+			k:         3,
+			breakWhen: alwaysFalse[string],
+			want: []string{
+				"[a a a]",
+				"[a a b]",
+				"[a b b]",
+				"[b b b]",
+			},
+		}, {
+			desc:      "break early",
+			elems:     []string{"a", "b"},
+			k:         3,
+			breakWhen: equal("[a b b]"),
+			want:      []string{"[a a a]", "[a a b]"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			seq := iterutil.CombinationsWithReplacement(tc.elems, tc.k)
+			got := iterutil.Map(seq, sprint)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleProduct() {
+	sizes := slices.Values([]string{"S", "M", "L"})
+	colors := slices.Values([]string{"red", "blue"})
+	for p := range iterutil.Product(sizes, colors) {
+		fmt.Println(p)
+	}
+	// Output:
+	// [S red]
+	// [S blue]
+	// [M red]
+	// [M blue]
+	// [L red]
+	// [L blue]
+}
+
+func TestProduct(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     [][]string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "no iterators",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[]"},
+		}, {
+			desc:      "one empty iterator",
+			elems:     [][]string{{"a", "b"}, {}, {"c"}},
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "no break",
+			elems:     [][]string{{"a", "b"}, {"c"}, {"d", "e"}},
+			breakWhen: alwaysFalse[string],
+			want: []string{
+				"[a c d]",
+				"[a c e]",
+				"[b c d]",
+				"[b c e]",
+			},
+		}, {
+			desc:      "break early",
+			elems:     [][]string{{"a", "b"}, {"c"}, {"d", "e"}},
+			breakWhen: equal("[b c d]"),
+			want:      []string{"[a c d]", "[a c e]"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			var seqs []iter.Seq[string]
+			for _, elems := range tc.elems {
+				seqs = append(seqs, slices.Values(elems))
+			}
+			got := iterutil.Map(iterutil.Product(seqs...), sprint)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExamplePowerSet() {
+	for s := range iterutil.PowerSet([]int{1, 2, 3}) {
+		fmt.Println(s)
+	}
+	// Output:
+	// []
+	// [1]
+	// [2]
+	// [3]
+	// [1 2]
+	// [1 3]
+	// [2 3]
+	// [1 2 3]
+}
+
+func TestPowerSet(t *testing.T) {
+	cases := []struct {
+		desc      string
+		elems     []string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "empty",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[]"},
+		}, {
+			desc:      "no break",
+			elems:     []string{"a", "b"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"[]", "[a]", "[b]", "[a b]"},
+		}, {
+			desc:      "break early",
+			elems:     []string{"a", "b"},
+			breakWhen: equal("[b]"),
+			want:      []string{"[]", "[a]"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.Map(iterutil.PowerSet(tc.elems), sprint)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleShuffled() {
+	s := []string{"foo", "bar", "baz", "qux"}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for e := range iterutil.Shuffled(s, rng) {
+		fmt.Println(e)
+	}
+	fmt.Println(s) // s is left untouched
+	// Output:
+	// foo
+	// baz
+	// bar
+	// qux
+	// [foo bar baz qux]
+}
+
+func TestShuffled(t *testing.T) {
+	cases := []struct {
+		desc  string
+		elems []string
+	}{
+		{
+			desc: "empty",
+		}, {
+			desc:  "one element",
+			elems: []string{"one"},
+		}, {
+			desc:  "several elements",
+			elems: []string{"one", "two", "three", "four", "five", "six"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			orig := slices.Clone(tc.elems)
+			seq := iterutil.Shuffled(tc.elems, rand.New(rand.NewPCG(1, 2)))
+			got := slices.Collect(seq)
+			if !slices.Equal(tc.elems, orig) {
+				t.Fatalf("slice was modified: got %v; want %v", tc.elems, orig)
+			}
+			if !slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(orig))) {
+				t.Fatalf("got %v; want a permutation of %v", got, orig)
+			}
+			// same seed, same order, even when breaking early
+			seq = iterutil.Shuffled(tc.elems, rand.New(rand.NewPCG(1, 2)))
+			assertEqual(t, seq, got, alwaysFalse[string])
+			for _, e := range got {
+				seq = iterutil.Shuffled(tc.elems, rand.New(rand.NewPCG(1, 2)))
+				want := got[:slices.Index(got, e)]
+				assertEqual(t, seq, want, equal(e))
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func TestShuffledUniformity(t *testing.T) {
+	const trials = 60000
+	s := []int{0, 1, 2}
+	rng := rand.New(rand.NewPCG(1, 2))
+	counts := make(map[[3]int]int)
+	for range trials {
+		var perm [3]int
+		for i, e := range iterutil.Enumerate[int](iterutil.Shuffled(s, rng)) {
+			perm[i] = e
+		}
+		counts[perm]++
+	}
+	if len(counts) != 6 {
+		t.Fatalf("got %d distinct permutations; want 6", len(counts))
+	}
+	const want = trials / 6
+	for perm, n := range counts {
+		if n < want*9/10 || want*11/10 < n {
+			t.Errorf("permutation %v occurred %d times; want about %d", perm, n, want)
+		}
+	}
+}
+
+func ExampleSortedFromMap() {
+	m := map[string]int{
+		"one":   1,
+		"two":   2,
+		"three": 3,
+	}
+	for k, v := range iterutil.SortedFromMap(m) {
+		fmt.Println(k, v)
+	}
+	// Output:
+	// one 1
+	// three 3
+	// two 2
+}
+
+func TestSortedFromMap(t *testing.T) {
+	cases := []struct {
+		desc      string
+		m         map[string]int
+		breakWhen func(string, int) bool
+		want      []Pair[string, int]
+	}{
+		{
+			desc: "no break",
+			m: map[string]int{
+				"one":   1,
+				"two":   2,
+				"three": 3,
+			},
+			breakWhen: alwaysFalse2[string, int],
+			want: []Pair[string, int]{
+				{"one", 1},
+				{"three", 3},
+				{"two", 2},
+			},
 		}, {
 			desc: "break early",
 			m: map[string]int{
@@ -513,14 +954,894 @@ func TestSortedFromMapFunc(t *testing.T) {
 			breakWhen: equal2("three", 3),
 			want: []Pair[string, int]{
 				{"one", 1},
-				{"two", 2},
 			},
 		},
 	}
 	for _, tc := range cases {
 		f := func(t *testing.T) {
-			got := iterutil.SortedFromMapFunc(tc.m, strings.Compare)
+			got := iterutil.SortedFromMap(tc.m)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+			// sanity check: also test referenceSortedFromMap
+			got = referenceSortedFromMap(tc.m)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func BenchmarkSortedFromMap(b *testing.B) {
+	type Case struct {
+		pairs    int
+		consumed string
+		breakAt  int
+	}
+	var cases []Case
+	const maxExp = 12
+
+	for n := range iterutil.Between(4, 11, 1) {
+		bc := Case{
+			pairs:    1 << n,
+			consumed: "at most 16",
+			breakAt:  min(16, 1<<n),
+		}
+		cases = append(cases, bc)
+
+		bc = Case{
+			pairs:    1 << n,
+			consumed: "half",
+			breakAt:  1 << (n - 1),
+		}
+		cases = append(cases, bc)
+
+		bc = Case{
+			pairs:    1 << n,
+			consumed: "all",
+			breakAt:  1 << n,
+		}
+		cases = append(cases, bc)
+	}
+	plusOne := func(i int) int { return i + 1 }
+	for _, bc := range cases {
+		seq := iterutil.Take(iterutil.Iterate(0, plusOne), bc.pairs)
+		m := make(map[int]struct{}, bc.pairs)
+		for k := range seq {
+			m[k] = struct{}{}
+		}
+		f := func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				for k := range iterutil.SortedFromMap(m) {
+					if k == bc.breakAt {
+						break
+					}
+				}
+			}
+		}
+		const tmpl = "impl=%s/pairs=%d/consumed=%s"
+		name := fmt.Sprintf(tmpl, "binary_heap", bc.pairs, bc.consumed)
+		b.Run(name, f)
+		f = func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				for k := range referenceSortedFromMap(m) {
+					if k == bc.breakAt {
+						break
+					}
+				}
+			}
+		}
+		name = fmt.Sprintf(tmpl, "upfront_sort", bc.pairs, bc.consumed)
+		b.Run(name, f)
+	}
+}
+
+func referenceSortedFromMap[M ~map[K]V, K cmp.Ordered, V any](m M) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ks := make([]K, 0, len(m))
+		for k := range m {
+			ks = append(ks, k)
+		}
+		slices.Sort(ks)
+		for _, k := range ks {
+			if !yield(k, m[k]) {
+				return
+			}
+		}
+	}
+}
+
+func ExampleSortedFromMapFunc() {
+	m := map[string]int{
+		"one":   1,
+		"two":   2,
+		"three": 3,
+	}
+	for k, v := range iterutil.SortedFromMapFunc(m, strings.Compare) {
+		fmt.Println(k, v)
+	}
+	// Output:
+	// one 1
+	// three 3
+	// two 2
+}
+
+func ExampleSortedFromMapFunc_incorrect() {
+	m := map[string]int{
+		"one":   1,
+		"two":   2,
+		"three": 3,
+	}
+	lenCmp := func(s1, s2 string) int { return cmp.Compare(len(s1), len(s2)) }
+	// Note that lenCmp does not correspond to a total order on strings.
+	// More specifically, lenCmp is not antisymmetric:
+	// for example, lenCmp("one", "two") = 0 and lenCmp("two", "one") = 0,
+	// but "one" != "two".
+	for k, v := range iterutil.SortedFromMapFunc(m, lenCmp) {
+		fmt.Println(k, v)
+	}
+	// Consequently, the output is nondeterministic; it may be either
+	//
+	// one 1
+	// two 2
+	// three 3
+	//
+	// or
+	//
+	// two 2
+	// one 1
+	// three 3
+}
+
+func TestSortedFromMapFunc(t *testing.T) {
+	cases := []struct {
+		desc      string
+		m         map[string]int
+		breakWhen func(string, int) bool
+		want      []Pair[string, int]
+	}{
+		{
+			desc: "no break",
+			m: map[string]int{
+				"one":   1,
+				"two":   2,
+				"three": 3,
+			},
+			breakWhen: alwaysFalse2[string, int],
+			want: []Pair[string, int]{
+				{"one", 1},
+				{"three", 3},
+				{"two", 2},
+			},
+		}, {
+			desc: "break early",
+			m: map[string]int{
+				"one":   1,
+				"two":   2,
+				"three": 3,
+			},
+			breakWhen: equal2("three", 3),
+			want: []Pair[string, int]{
+				{"one", 1},
+				{"two", 2},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.SortedFromMapFunc(tc.m, strings.Compare)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleSplitString() {
+	for s := range iterutil.SplitString("a,b,c", ",") {
+		fmt.Printf("%q\n", s)
+	}
+	// Output:
+	// "a"
+	// "b"
+	// "c"
+}
+
+func TestSplitString(t *testing.T) {
+	cases := []struct {
+		desc      string
+		s         string
+		sep       string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "empty string",
+			sep:       ",",
+			breakWhen: alwaysFalse[string],
+			want:      []string{""},
+		}, {
+			desc:      "empty string and separator",
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "empty separator",
+			s:         "a\xffé",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "\xff", "é"},
+		}, {
+			desc:      "no break",
+			s:         ",a,,b,",
+			sep:       ",",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"", "a", "", "b", ""},
+		}, {
+			desc:      "multibyte separator",
+			s:         "a::b:c",
+			sep:       "::",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "b:c"},
+		}, {
+			desc:      "break early",
+			s:         "a,b,c",
+			sep:       ",",
+			breakWhen: equal("b"),
+			want:      []string{"a"},
+		}, {
+			desc:      "empty separator break early",
+			s:         "abc",
+			breakWhen: equal("c"),
+			want:      []string{"a", "b"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.SplitString(tc.s, tc.sep)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func FuzzSplitString(f *testing.F) {
+	f.Add("", "")
+	f.Add("a,b,c", ",")
+	f.Add("a\xffé", "")
+	f.Add("aaaa", "aa")
+	f.Fuzz(func(t *testing.T, s, sep string) {
+		got := iterutil.SplitString(s, sep)
+		iterutiltest.AssertSeq(t, got, strings.Split(s, sep))
+	})
+}
+
+func ExampleFields() {
+	for s := range iterutil.Fields("  foo bar\tbaz\n") {
+		fmt.Printf("%q\n", s)
+	}
+	// Output:
+	// "foo"
+	// "bar"
+	// "baz"
+}
+
+func TestFields(t *testing.T) {
+	cases := []struct {
+		desc      string
+		s         string
+		breakWhen func(string) bool
+		want      []string
+	}{
+		{
+			desc:      "empty",
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "only spaces",
+			s:         " \t\n ",
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "no break",
+			s:         "foo\u00a0bar  baz",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"foo", "bar", "baz"},
+		}, {
+			desc:      "break early",
+			s:         " foo bar baz ",
+			breakWhen: equal("baz"),
+			want:      []string{"foo", "bar"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.Fields(tc.s)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func FuzzFields(f *testing.F) {
+	f.Add("")
+	f.Add("  foo bar\tbaz\n")
+	f.Add("a\xff b")
+	f.Fuzz(func(t *testing.T, s string) {
+		iterutiltest.AssertSeq(t, iterutil.Fields(s), strings.Fields(s))
+		isPunct := func(r rune) bool { return unicode.IsPunct(r) }
+		got := iterutil.FieldsFunc(s, isPunct)
+		iterutiltest.AssertSeq(t, got, strings.FieldsFunc(s, isPunct))
+	})
+}
+
+func ExampleFieldsFunc() {
+	isComma := func(r rune) bool { return r == ',' }
+	for s := range iterutil.FieldsFunc("foo,,bar,baz,", isComma) {
+		fmt.Printf("%q\n", s)
+	}
+	// Output:
+	// "foo"
+	// "bar"
+	// "baz"
+}
+
+func TestSplittersDoNotAllocate(t *testing.T) {
+	const s = "  foo bar,baz\tqux  héllo\n"
+	isComma := func(r rune) bool { return r == ',' }
+	var n int
+	cases := []struct {
+		desc string
+		f    func()
+	}{
+		{
+			desc: "SplitString",
+			f: func() {
+				for range iterutil.SplitString(s, " ") {
+					n++
+				}
+			},
+		}, {
+			desc: "SplitString with empty separator",
+			f: func() {
+				for range iterutil.SplitString(s, "") {
+					n++
+				}
+			},
+		}, {
+			desc: "Fields",
+			f: func() {
+				for range iterutil.Fields(s) {
+					n++
+				}
+			},
+		}, {
+			desc: "FieldsFunc",
+			f: func() {
+				for range iterutil.FieldsFunc(s, isComma) {
+					n++
+				}
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tc.f); allocs != 0 {
+				t.Errorf("got %v allocs; want 0", allocs)
+			}
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleRunes() {
+	for i, r := range iterutil.Runes("héllo") {
+		fmt.Println(i, string(r))
+	}
+	// Output:
+	// 0 h
+	// 1 é
+	// 3 l
+	// 4 l
+	// 5 o
+}
+
+func TestRunes(t *testing.T) {
+	cases := []struct {
+		desc      string
+		s         string
+		breakWhen func(int, rune) bool
+		want      []Pair[int, rune]
+	}{
+		{
+			desc:      "no break",
+			s:         "a\xffé",
+			breakWhen: alwaysFalse2[int, rune],
+			want: []Pair[int, rune]{
+				{0, 'a'},
+				{1, '\uFFFD'},
+				{2, 'é'},
+			},
+		}, {
+			desc:      "break early",
+			s:         "aéb",
+			breakWhen: equal2(3, 'b'),
+			want: []Pair[int, rune]{
+				{0, 'a'},
+				{1, 'é'},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.Runes(tc.s)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleRegexpMatches() {
+	re := regexp.MustCompile(`(\w+)=(\d+)`)
+	s := "a=1, b=22, c=x, d=333"
+	for m := range iterutil.RegexpMatches(re, s) {
+		fmt.Printf("%q\n", m)
+	}
+	// Output:
+	// ["a=1" "a" "1"]
+	// ["b=22" "b" "22"]
+	// ["d=333" "d" "333"]
+}
+
+func ExampleRegexpMatchIndices() {
+	re := regexp.MustCompile(`a(x*)b`)
+	s := "-ab-axxb-"
+	for m := range iterutil.RegexpMatchIndices(re, s) {
+		fmt.Println(m)
+	}
+	// Output:
+	// [1 3 2 2]
+	// [4 8 5 7]
+}
+
+func TestRegexpMatches(t *testing.T) {
+	cases := []struct {
+		desc      string
+		re        string
+		s         string
+		breakWhen func(string) bool
+		want      []string // matches joined with "|"
+	}{
+		{
+			desc:      "no match",
+			re:        `a+`,
+			s:         "bcd",
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "unmatched group",
+			re:        `a(b)?`,
+			s:         "ab a",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"ab|b", "a|"},
+		}, {
+			desc:      "empty matches",
+			re:        `x*`,
+			s:         "axxb",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"", "xx", ""},
+		}, {
+			desc:      "word boundary",
+			re:        `\bfo+`,
+			s:         "foo afoo fo",
+			breakWhen: alwaysFalse[string],
+			want:      []string{"foo", "fo"},
+		}, {
+			desc:      "break early",
+			re:        `\d+`,
+			s:         "1 22 333",
+			breakWhen: equal("333"),
+			want:      []string{"1", "22"},
+		}, {
+			desc:      "break early with word boundary",
+			re:        `\b\d+`,
+			s:         "1 22 333",
+			breakWhen: equal("333"),
+			want:      []string{"1", "22"},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			re := regexp.MustCompile(tc.re)
+			matches := iterutil.RegexpMatches(re, tc.s)
+			join := func(m []string) string { return strings.Join(m, "|") }
+			got := iterutil.Map(matches, join)
+			assertEqual(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func FuzzRegexpMatches(f *testing.F) {
+	f.Add(`a(x*)b`, "-ab-axxb-")
+	f.Add(`x*`, "axxb")
+	f.Add(`^a|b$`, "ab\nab")
+	f.Add(`(?m)^a|b$`, "ab\nab")
+	f.Add(`\bfo+\B`, "foo afoo fo")
+	f.Add(`(a)|(b)`, "abc")
+	f.Add(`.*?`, "é\xff")
+	f.Fuzz(func(t *testing.T, expr, s string) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			t.Skip()
+		}
+		join := func(m []string) string { return strings.Join(m, "|") }
+		got := iterutil.Map(iterutil.RegexpMatches(re, s), join)
+		var want []string
+		for _, m := range re.FindAllStringSubmatch(s, -1) {
+			want = append(want, join(m))
+		}
+		iterutiltest.AssertSeq(t, got, want)
+		gotIndices := iterutil.Map(iterutil.RegexpMatchIndices(re, s), sprint)
+		var wantIndices []string
+		for _, m := range re.FindAllStringSubmatchIndex(s, -1) {
+			wantIndices = append(wantIndices, sprint(m))
+		}
+		iterutiltest.AssertSeq(t, gotIndices, wantIndices)
+	})
+}
+
+// heapChildren returns a function that yields the children of a node
+// in a binary heap of the specified size (infinite if size is negative).
+func heapChildren(size int) func(int) iter.Seq[int] {
+	return func(n int) iter.Seq[int] {
+		return iterutil.TakeWhile(
+			iterutil.SeqOf(2*n+1, 2*n+2),
+			func(i int) bool { return size < 0 || i < size },
+		)
+	}
+}
+
+func ExampleDFS() {
+	type node struct {
+		name     string
+		children []node
+	}
+	root := node{"/", []node{
+		{"etc", []node{{"hosts", nil}}},
+		{"usr", []node{{"bin", nil}, {"lib", nil}}},
+	}}
+	children := func(n node) iter.Seq[node] { return slices.Values(n.children) }
+	for n, depth := range iterutil.DFS(root, children, -1) {
+		fmt.Printf("%s%s\n", strings.Repeat("  ", depth), n.name)
+	}
+	// Output:
+	// /
+	//   etc
+	//     hosts
+	//   usr
+	//     bin
+	//     lib
+}
+
+func TestDFS(t *testing.T) {
+	cases := []struct {
+		desc      string
+		size      int
+		maxDepth  int
+		breakWhen func(int, int) bool
+		want      []Pair[int, int]
+	}{
+		{
+			desc:      "root only",
+			size:      1,
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[int, int],
+			want:      []Pair[int, int]{{0, 0}},
+		}, {
+			desc:      "no break",
+			size:      7,
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[int, int],
+			want: []Pair[int, int]{
+				{0, 0}, {1, 1}, {3, 2}, {4, 2}, {2, 1}, {5, 2}, {6, 2},
+			},
+		}, {
+			desc:      "max depth",
+			size:      7,
+			maxDepth:  1,
+			breakWhen: alwaysFalse2[int, int],
+			want:      []Pair[int, int]{{0, 0}, {1, 1}, {2, 1}},
+		}, {
+			desc:      "max depth zero",
+			size:      7,
+			maxDepth:  0,
+			breakWhen: alwaysFalse2[int, int],
+			want:      []Pair[int, int]{{0, 0}},
+		}, {
+			desc:      "break early",
+			size:      7,
+			maxDepth:  -1,
+			breakWhen: equal2(2, 1),
+			want:      []Pair[int, int]{{0, 0}, {1, 1}, {3, 2}, {4, 2}},
+		}, {
+			desc:      "break early in infinite tree",
+			size:      -1,
+			maxDepth:  -1,
+			breakWhen: equal2(15, 4),
+			want:      []Pair[int, int]{{0, 0}, {1, 1}, {3, 2}, {7, 3}},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.DFS(0, heapChildren(tc.size), tc.maxDepth)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExamplePostOrder() {
+	// children returns the divisors of n that are greater than 1
+	// and smaller than n, in ascending order.
+	children := func(n int) iter.Seq[int] {
+		return iterutil.Filter(iterutil.Between(2, n, 1), func(d int) bool {
+			return n%d == 0
+		})
+	}
+	for n, depth := range iterutil.PostOrder(12, children, 1) {
+		fmt.Println(n, depth)
+	}
+	// Output:
+	// 2 1
+	// 3 1
+	// 4 1
+	// 6 1
+	// 12 0
+}
+
+func TestPostOrder(t *testing.T) {
+	cases := []struct {
+		desc      string
+		size      int
+		maxDepth  int
+		breakWhen func(int, int) bool
+		want      []Pair[int, int]
+	}{
+		{
+			desc:      "root only",
+			size:      1,
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[int, int],
+			want:      []Pair[int, int]{{0, 0}},
+		}, {
+			desc:      "no break",
+			size:      7,
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[int, int],
+			want: []Pair[int, int]{
+				{3, 2}, {4, 2}, {1, 1}, {5, 2}, {6, 2}, {2, 1}, {0, 0},
+			},
+		}, {
+			desc:      "max depth",
+			size:      7,
+			maxDepth:  1,
+			breakWhen: alwaysFalse2[int, int],
+			want:      []Pair[int, int]{{1, 1}, {2, 1}, {0, 0}},
+		}, {
+			desc:      "break early",
+			size:      7,
+			maxDepth:  -1,
+			breakWhen: equal2(5, 2),
+			want:      []Pair[int, int]{{3, 2}, {4, 2}, {1, 1}},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.PostOrder(0, heapChildren(tc.size), tc.maxDepth)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleBFS() {
+	children := func(s string) iter.Seq[string] {
+		return iterutil.SeqOf(s+"0", s+"1")
+	}
+	for s, depth := range iterutil.BFS("", children, 2) {
+		fmt.Printf("%d %q\n", depth, s)
+	}
+	// Output:
+	// 0 ""
+	// 1 "0"
+	// 1 "1"
+	// 2 "00"
+	// 2 "01"
+	// 2 "10"
+	// 2 "11"
+}
+
+func TestBFS(t *testing.T) {
+	cases := []struct {
+		desc      string
+		size      int
+		maxDepth  int
+		breakWhen func(int, int) bool
+		want      []Pair[int, int]
+	}{
+		{
+			desc:      "root only",
+			size:      1,
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[int, int],
+			want:      []Pair[int, int]{{0, 0}},
+		}, {
+			desc:      "no break",
+			size:      7,
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[int, int],
+			want: []Pair[int, int]{
+				{0, 0}, {1, 1}, {2, 1}, {3, 2}, {4, 2}, {5, 2}, {6, 2},
+			},
+		}, {
+			desc:      "max depth",
+			size:      7,
+			maxDepth:  1,
+			breakWhen: alwaysFalse2[int, int],
+			want:      []Pair[int, int]{{0, 0}, {1, 1}, {2, 1}},
+		}, {
+			desc:      "break early",
+			size:      7,
+			maxDepth:  -1,
+			breakWhen: equal2(4, 2),
+			want:      []Pair[int, int]{{0, 0}, {1, 1}, {2, 1}, {3, 2}},
+		}, {
+			desc:      "break early in infinite tree",
+			size:      -1,
+			maxDepth:  -1,
+			breakWhen: equal2(7, 3),
+			want: []Pair[int, int]{
+				{0, 0}, {1, 1}, {2, 1}, {3, 2}, {4, 2}, {5, 2}, {6, 2},
+			},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.BFS(0, heapChildren(tc.size), tc.maxDepth)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+// graph is a directed graph, represented as adjacency lists,
+// that contains several cycles.
+var graph = map[string][]string{
+	"a": {"b", "c"},
+	"b": {"d", "a"},
+	"c": {"d"},
+	"d": {"a"},
+	"e": {"a"},
+}
+
+func neighbors(n string) iter.Seq[string] {
+	return slices.Values(graph[n])
+}
+
+func ExampleBFSGraph() {
+	for n, dist := range iterutil.BFSGraph("a", neighbors, -1) {
+		fmt.Println(n, dist)
+	}
+	// Output:
+	// a 0
+	// b 1
+	// c 1
+	// d 2
+}
+
+func TestBFSGraph(t *testing.T) {
+	cases := []struct {
+		desc      string
+		root      string
+		maxDepth  int
+		breakWhen func(string, int) bool
+		want      []Pair[string, int]
+	}{
+		{
+			desc:      "no neighbors",
+			root:      "z",
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[string, int],
+			want:      []Pair[string, int]{{"z", 0}},
+		}, {
+			desc:      "no break",
+			root:      "a",
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[string, int],
+			want:      []Pair[string, int]{{"a", 0}, {"b", 1}, {"c", 1}, {"d", 2}},
+		}, {
+			desc:      "other root",
+			root:      "e",
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[string, int],
+			want: []Pair[string, int]{
+				{"e", 0}, {"a", 1}, {"b", 2}, {"c", 2}, {"d", 3},
+			},
+		}, {
+			desc:      "max depth",
+			root:      "a",
+			maxDepth:  1,
+			breakWhen: alwaysFalse2[string, int],
+			want:      []Pair[string, int]{{"a", 0}, {"b", 1}, {"c", 1}},
+		}, {
+			desc:      "break early",
+			root:      "a",
+			maxDepth:  -1,
+			breakWhen: equal2("c", 1),
+			want:      []Pair[string, int]{{"a", 0}, {"b", 1}},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.BFSGraph(tc.root, neighbors, tc.maxDepth)
+			assertEqual2(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, iterutil.Left(got))
+		}
+		t.Run(tc.desc, f)
+	}
+}
+
+func ExampleDFSGraph() {
+	for n, depth := range iterutil.DFSGraph("a", neighbors, -1) {
+		fmt.Println(n, depth)
+	}
+	// Output:
+	// a 0
+	// b 1
+	// d 2
+	// c 1
+}
+
+func TestDFSGraph(t *testing.T) {
+	cases := []struct {
+		desc      string
+		root      string
+		maxDepth  int
+		breakWhen func(string, int) bool
+		want      []Pair[string, int]
+	}{
+		{
+			desc:      "no neighbors",
+			root:      "z",
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[string, int],
+			want:      []Pair[string, int]{{"z", 0}},
+		}, {
+			desc:      "no break",
+			root:      "a",
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[string, int],
+			want:      []Pair[string, int]{{"a", 0}, {"b", 1}, {"d", 2}, {"c", 1}},
+		}, {
+			desc:      "other root",
+			root:      "e",
+			maxDepth:  -1,
+			breakWhen: alwaysFalse2[string, int],
+			want: []Pair[string, int]{
+				{"e", 0}, {"a", 1}, {"b", 2}, {"d", 3}, {"c", 2},
+			},
+		}, {
+			desc:      "max depth",
+			root:      "a",
+			maxDepth:  1,
+			breakWhen: alwaysFalse2[string, int],
+			want:      []Pair[string, int]{{"a", 0}, {"b", 1}, {"c", 1}},
+		}, {
+			desc:      "break early",
+			root:      "a",
+			maxDepth:  -1,
+			breakWhen: equal2("d", 2),
+			want:      []Pair[string, int]{{"a", 0}, {"b", 1}},
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			got := iterutil.DFSGraph(tc.root, neighbors, tc.maxDepth)
 			assertEqual2(t, got, tc.want, tc.breakWhen)
+			iterutiltest.CheckReiterable(t, iterutil.Left(got))
 		}
 		t.Run(tc.desc, f)
 	}
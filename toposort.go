@@ -0,0 +1,165 @@
+package iterutil
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/jub0bs/iterutil/internal"
+)
+
+// TopoSort returns an iterator over nodes and, transitively, their
+// dependencies, in [topological order]:
+// each node is yielded after all of its dependencies,
+// as reported by deps.
+// Among nodes whose dependencies have all been yielded,
+// TopoSort yields first the ones it discovered first.
+//
+// Each node is yielded along with a nil error.
+// If the dependency graph contains a cycle, the resulting iterator,
+// after yielding all the nodes that do not (directly or transitively)
+// depend on a cycle, yields the zero value of N along with a [*CycleError]
+// and ends.
+//
+// TopoSort ranges over nodes exhaustively and calls deps once per node
+// before yielding anything, at the beginning of each iteration;
+// it terminates if and only if nodes is finite
+// and the dependency graph has finitely many nodes.
+//
+// [topological order]: https://en.wikipedia.org/wiki/Topological_sorting
+func TopoSort[N comparable](nodes iter.Seq[N], deps func(N) iter.Seq[N]) iter.Seq2[N, error] {
+	return topoSort(nodes, deps, nil)
+}
+
+// TopoSortFunc is like [TopoSort] but, among nodes whose dependencies
+// have all been yielded, yields first the least one according to cmp,
+// which makes the resulting order independent of the order in which
+// nodes are discovered.
+func TopoSortFunc[N comparable](
+	nodes iter.Seq[N],
+	deps func(N) iter.Seq[N],
+	cmp func(N, N) int,
+) iter.Seq2[N, error] {
+	return topoSort(nodes, deps, cmp)
+}
+
+// topoSort implements Kahn's algorithm. Nodes are identified by their
+// order of discovery. If cmp is nil, ready nodes are processed in order of
+// discovery; otherwise, they're processed in the order specified by cmp.
+func topoSort[N comparable](
+	nodes iter.Seq[N],
+	deps func(N) iter.Seq[N],
+	cmp func(N, N) int,
+) iter.Seq2[N, error] {
+	return func(yield func(N, error) bool) {
+		index := make(map[N]int)
+		var all []N
+		add := func(n N) int {
+			i, found := index[n]
+			if !found {
+				i = len(all)
+				index[n] = i
+				all = append(all, n)
+			}
+			return i
+		}
+		for n := range nodes {
+			add(n)
+		}
+		var depsOf [][]int
+		for i := 0; i < len(all); i++ { // all grows as dependencies are discovered
+			var ds []int
+			for d := range deps(all[i]) {
+				ds = append(ds, add(d))
+			}
+			depsOf = append(depsOf, ds)
+		}
+		indegree := make([]int, len(all))
+		dependents := make([][]int, len(all))
+		for i, ds := range depsOf {
+			indegree[i] = len(ds)
+			for _, d := range ds {
+				dependents[d] = append(dependents[d], i)
+			}
+		}
+		var ready []int
+		for i, deg := range indegree {
+			if deg == 0 {
+				ready = append(ready, i)
+			}
+		}
+		order := func(i, j int) int { return i - j }
+		if cmp != nil {
+			order = func(i, j int) int { return cmp(all[i], all[j]) }
+		}
+		h := internal.NewHeapFunc(ready, order)
+		var yielded int
+		for h.Len() > 0 {
+			var i int
+			i, h = h.Pop()
+			if !yield(all[i], nil) {
+				return
+			}
+			yielded++
+			for _, j := range dependents[i] {
+				indegree[j]--
+				if indegree[j] == 0 {
+					h = h.Push(j)
+				}
+			}
+		}
+		if yielded < len(all) {
+			var zero N
+			yield(zero, &CycleError[N]{Cycle: findCycle(all, depsOf, indegree)})
+		}
+	}
+}
+
+// findCycle returns a dependency cycle among the nodes whose indegree is
+// positive. Because each of those nodes depends on at least one other such
+// node, following such dependencies eventually leads to a cycle.
+func findCycle[N any](all []N, depsOf [][]int, indegree []int) []N {
+	pos := make(map[int]int) // node -> position in path
+	var path []int
+	i := slices.IndexFunc(indegree, func(deg int) bool { return deg > 0 })
+	for {
+		if p, found := pos[i]; found {
+			path = append(path[p:], i)
+			break
+		}
+		pos[i] = len(path)
+		path = append(path, i)
+		for _, d := range depsOf[i] {
+			if indegree[d] > 0 {
+				i = d
+				break
+			}
+		}
+	}
+	cycle := make([]N, len(path))
+	for k, i := range path {
+		cycle[k] = all[i]
+	}
+	return cycle
+}
+
+// A CycleError reports a dependency cycle detected by [TopoSort] or
+// [TopoSortFunc].
+type CycleError[N any] struct {
+	// Cycle lists the nodes of the cycle, each of which depends on the next;
+	// its first and last elements are the same node.
+	Cycle []N
+}
+
+func (e *CycleError[N]) Error() string {
+	var sb strings.Builder
+	sb.WriteString("iterutil: dependency cycle: ")
+	for i, n := range e.Cycle {
+		if i > 0 {
+			sb.WriteString(" -> ")
+		}
+		fmt.Fprint(&sb, n)
+	}
+	return sb.String()
+}
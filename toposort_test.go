@@ -0,0 +1,234 @@
+package iterutil_test
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jub0bs/iterutil"
+	"github.com/jub0bs/iterutil/iterutiltest"
+)
+
+func ExampleTopoSort() {
+	deps := map[string][]string{
+		"app":  {"http", "db"},
+		"http": {"log"},
+		"db":   {"log"},
+	}
+	depsOf := func(pkg string) iter.Seq[string] {
+		return slices.Values(deps[pkg])
+	}
+	for pkg, err := range iterutil.TopoSort(iterutil.SeqOf("app"), depsOf) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(pkg)
+	}
+	// Output:
+	// log
+	// http
+	// db
+	// app
+}
+
+func ExampleTopoSort_cycle() {
+	deps := map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"b"},
+	}
+	depsOf := func(n string) iter.Seq[string] {
+		return slices.Values(deps[n])
+	}
+	for n, err := range iterutil.TopoSort(iterutil.SeqOf("a", "d"), depsOf) {
+		var cerr *iterutil.CycleError[string]
+		if errors.As(err, &cerr) {
+			fmt.Println(cerr.Cycle)
+			return
+		}
+		fmt.Println(n)
+	}
+	// Output:
+	// d
+	// [b c b]
+}
+
+func ExampleTopoSortFunc() {
+	deps := map[string][]string{
+		"app":  {"http", "db"},
+		"http": {"log"},
+		"db":   {"log"},
+	}
+	depsOf := func(pkg string) iter.Seq[string] {
+		return slices.Values(deps[pkg])
+	}
+	nodes := maps.Keys(deps) // in unspecified order
+	for pkg, err := range iterutil.TopoSortFunc(nodes, depsOf, strings.Compare) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(pkg)
+	}
+	// Output:
+	// log
+	// db
+	// http
+	// app
+}
+
+func TestTopoSort(t *testing.T) {
+	cases := []struct {
+		desc      string
+		nodes     []string
+		deps      map[string][]string
+		cmp       func(string, string) int
+		breakWhen func(string) bool
+		want      []string
+		wantErr   string
+	}{
+		{
+			desc:      "empty",
+			breakWhen: alwaysFalse[string],
+		}, {
+			desc:      "no dependencies",
+			nodes:     []string{"b", "a", "c"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"b", "a", "c"},
+		}, {
+			desc:      "no dependencies with cmp",
+			nodes:     []string{"b", "a", "c"},
+			cmp:       strings.Compare,
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "b", "c"},
+		}, {
+			desc:      "duplicate nodes",
+			nodes:     []string{"a", "b", "a"},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "b"},
+		}, {
+			desc:  "chain discovered through dependencies",
+			nodes: []string{"c"},
+			deps: map[string][]string{
+				"c": {"b"},
+				"b": {"a"},
+			},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "b", "c"},
+		}, {
+			desc:  "diamond",
+			nodes: []string{"app"},
+			deps: map[string][]string{
+				"app":  {"lib", "util"},
+				"lib":  {"core"},
+				"util": {"core"},
+			},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"core", "lib", "util", "app"},
+		}, {
+			desc:  "diamond with reverse cmp",
+			nodes: []string{"app"},
+			deps: map[string][]string{
+				"app":  {"lib", "util"},
+				"lib":  {"core"},
+				"util": {"core"},
+			},
+			cmp: func(a, b string) int {
+				return strings.Compare(b, a)
+			},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"core", "util", "lib", "app"},
+		}, {
+			desc:  "duplicate dependencies",
+			nodes: []string{"b"},
+			deps: map[string][]string{
+				"b": {"a", "a"},
+			},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"a", "b"},
+		}, {
+			desc:  "break early",
+			nodes: []string{"app"},
+			deps: map[string][]string{
+				"app":  {"lib", "util"},
+				"lib":  {"core"},
+				"util": {"core"},
+			},
+			breakWhen: equal("util"),
+			want:      []string{"core", "lib"},
+		}, {
+			desc:  "self-dependency",
+			nodes: []string{"a", "b"},
+			deps: map[string][]string{
+				"a": {"a"},
+			},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"b"},
+			wantErr:   "iterutil: dependency cycle: a -> a",
+		}, {
+			desc:  "cycle",
+			nodes: []string{"a", "d"},
+			deps: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"b"},
+				"e": {"d"},
+			},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"d"},
+			wantErr:   "iterutil: dependency cycle: b -> c -> b",
+		}, {
+			desc:  "nodes downstream of a cycle",
+			nodes: []string{"x", "a"},
+			deps: map[string][]string{
+				"x": {"a"},
+				"a": {"b", "y"},
+				"b": {"a"},
+			},
+			breakWhen: alwaysFalse[string],
+			want:      []string{"y"},
+			wantErr:   "iterutil: dependency cycle: a -> b -> a",
+		},
+	}
+	for _, tc := range cases {
+		f := func(t *testing.T) {
+			deps := func(n string) iter.Seq[string] {
+				return slices.Values(tc.deps[n])
+			}
+			nodes := slices.Values(tc.nodes)
+			var seq iter.Seq2[string, error]
+			if tc.cmp == nil {
+				seq = iterutil.TopoSort(nodes, deps)
+			} else {
+				seq = iterutil.TopoSortFunc(nodes, deps, tc.cmp)
+			}
+			var (
+				got    []string
+				gotErr string
+			)
+			for n, err := range iterutil.Checked2(seq) {
+				if err != nil {
+					gotErr = err.Error()
+					break
+				}
+				if tc.breakWhen(n) {
+					break
+				}
+				got = append(got, n)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+			if gotErr != tc.wantErr {
+				t.Errorf("got error %q; want %q", gotErr, tc.wantErr)
+			}
+			iterutiltest.CheckReiterable(t, iterutil.Left(seq))
+		}
+		t.Run(tc.desc, f)
+	}
+}
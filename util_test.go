@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"iter"
 	"testing"
+
+	"github.com/jub0bs/iterutil"
 )
 
 func assertEqual[E comparable](
@@ -15,7 +17,7 @@ func assertEqual[E comparable](
 	t.Helper()
 	var es []E
 	var i int
-	for e := range got {
+	for e := range iterutil.Checked(got) {
 		if breakWhen(e) {
 			return
 		}
@@ -53,7 +55,7 @@ func assertEqual2[K, V comparable](
 	t.Helper()
 	var pairs []Pair[K, V]
 	var i int
-	for k, v := range got {
+	for k, v := range iterutil.Checked2(got) {
 		if breakWhen(k, v) {
 			return
 		}
@@ -111,3 +113,17 @@ func equal2[K, V comparable](key K, value V) func(K, V) bool {
 		return k == key && v == value
 	}
 }
+
+func sprint[E any](e E) string {
+	return fmt.Sprint(e)
+}
+
+func assertPanics(t *testing.T, f func(), want any) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != want {
+			t.Errorf("got panic %v; want %v", r, want)
+		}
+	}()
+	f()
+}